@@ -0,0 +1,178 @@
+package scanner
+
+import (
+	"errors"
+	"strconv"
+)
+
+// #region Balanced
+
+// Quote is a string delimiter. Escape is the escape
+// byte, like '\\', or zero for raw strings.
+type Quote struct {
+	Open, Close string
+	Escape      byte
+}
+
+// Balanced describes balanced delimiters.
+type Balanced struct {
+	// Brackets, like {"(", ")"} and {"[", "]"}.
+	Brackets []Pair
+	// Strings, whose brackets are ignored.
+	Quotes []Quote
+	// Comments, whose brackets are ignored.
+	Trivia *Trivia
+	// MaxDepth limits the nesting. Zero means no limit.
+	MaxDepth int
+}
+
+// Balanced delimiters of some languages.
+var (
+	BalancedC = Balanced{
+		Brackets: []Pair{{"(", ")"}, {"[", "]"}, {"{", "}"}},
+		Quotes:   []Quote{{`"`, `"`, '\\'}, {`'`, `'`, '\\'}},
+		Trivia:   &TriviaC,
+	}
+	BalancedJSON = Balanced{
+		Brackets: []Pair{{"[", "]"}, {"{", "}"}},
+		Quotes:   []Quote{{`"`, `"`, '\\'}},
+	}
+)
+
+// Errors of BalanceError.
+var (
+	ErrMismatched = errors.New("mismatched delimiter")
+	ErrUnclosed   = errors.New("unclosed delimiter")
+	ErrTooDeep    = errors.New("nesting too deep")
+)
+
+// BalanceError records a mismatched or unclosed delimiter.
+type BalanceError struct {
+	Pos   int    // Byte offset, from where the scan started, of the delimiter.
+	Delim string // The delimiter.
+	Err   error  // Either ErrMismatched, ErrUnclosed or ErrTooDeep.
+}
+
+func (e *BalanceError) Error() string {
+	return "scanner: " + e.Err.Error() + " " + strconv.Quote(e.Delim) + " at " + strconv.Itoa(e.Pos)
+}
+
+func (e *BalanceError) Unwrap() error {
+	return e.Err
+}
+
+// UtilMatchBalanced matches balanced brackets, skipping
+// strings and comments. It returns false and no error if
+// there is no open bracket at the start. Otherwise it
+// returns an error on the first mismatched delimiter or
+// on the innermost unclosed one.
+func (s *Scanner) UtilMatchBalanced(b *Balanced) (bool, error) {
+	ss := *s
+	if b.open(ss) < 0 {
+		return false, nil
+	}
+	type open struct {
+		bracket int
+		pos     int
+	}
+	var buf [32]open
+	stack := buf[:0]
+	for i := 0; i < len(ss); {
+		rest := ss[i:]
+		// Strings and comments.
+		if q, ok := b.quote(rest); ok {
+			n, ok := rest.matchQuote(q)
+			if !ok {
+				return false, &BalanceError{Pos: i, Delim: q.Open, Err: ErrUnclosed}
+			}
+			i += n
+			continue
+		}
+		if t := b.Trivia; t != nil {
+			if t.line(rest) {
+				i += rest.matchLine()
+				continue
+			}
+			if p, ok := t.block(rest); ok {
+				n, ok := rest.matchBlock(p, t.Nested)
+				if !ok {
+					return false, &BalanceError{Pos: i, Delim: p.Open, Err: ErrUnclosed}
+				}
+				i += n
+				continue
+			}
+		}
+		// Brackets.
+		if k := b.open(rest); k >= 0 {
+			if b.MaxDepth > 0 && len(stack) == b.MaxDepth {
+				return false, &BalanceError{Pos: i, Delim: b.Brackets[k].Open, Err: ErrTooDeep}
+			}
+			stack = append(stack, open{bracket: k, pos: i})
+			i += len(b.Brackets[k].Open)
+			continue
+		}
+		if k := b.close(rest); k >= 0 {
+			if top := stack[len(stack)-1]; top.bracket != k &&
+				b.Brackets[top.bracket].Close != b.Brackets[k].Close {
+				return false, &BalanceError{Pos: i, Delim: b.Brackets[k].Close, Err: ErrMismatched}
+			}
+			stack = stack[:len(stack)-1]
+			i += len(b.Brackets[k].Close)
+			if len(stack) == 0 {
+				*s = ss[i:]
+				return true, nil
+			}
+			continue
+		}
+		i++
+	}
+	top := stack[len(stack)-1]
+	return false, &BalanceError{Pos: top.pos, Delim: b.Brackets[top.bracket].Open, Err: ErrUnclosed}
+}
+
+// open returns the index of the bracket that opens at ss or -1.
+func (b *Balanced) open(ss Scanner) int {
+	for i, p := range b.Brackets {
+		if ss.Equal(p.Open) {
+			return i
+		}
+	}
+	return -1
+}
+
+// close returns the index of the bracket that closes at ss or -1.
+func (b *Balanced) close(ss Scanner) int {
+	for i, p := range b.Brackets {
+		if ss.Equal(p.Close) {
+			return i
+		}
+	}
+	return -1
+}
+
+// quote returns the string that opens at ss.
+func (b *Balanced) quote(ss Scanner) (Quote, bool) {
+	for _, q := range b.Quotes {
+		if ss.Equal(q.Open) {
+			return q, true
+		}
+	}
+	return Quote{}, false
+}
+
+// matchQuote returns the length of the string q that starts at s.
+func (s Scanner) matchQuote(q Quote) (int, bool) {
+	for i := len(q.Open); i < len(s); {
+		if q.Escape != 0 && s[i] == q.Escape {
+			i += 2
+			continue
+		}
+		if s[i:].Equal(q.Close) {
+			return i + len(q.Close), true
+		}
+		i++
+	}
+	return 0, false
+}
+
+// #endregion Balanced
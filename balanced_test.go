@@ -0,0 +1,60 @@
+package scanner
+
+import (
+	"errors"
+	"testing"
+)
+
+// #region Balanced
+
+func TestScannerUtilMatchBalanced(t *testing.T) {
+	tt := []struct {
+		give string
+		when Balanced
+		then bool
+		err  error
+		pos  int
+		exp  string
+	}{
+		{give: `() x`, when: BalancedC, then: true, exp: `()`},
+		{give: `(a[b]{c}) x`, when: BalancedC, then: true, exp: `(a[b]{c})`},
+		{give: `(")" ')' /* ) */ // )` + "\n) x", when: BalancedC, then: true, exp: `(")" ')' /* ) */ // )` + "\n)"},
+		{give: `("\")") x`, when: BalancedC, then: true, exp: `("\")")`},
+		{give: `([)]`, when: BalancedC, err: ErrMismatched, pos: 2},
+		{give: `(a[b}`, when: BalancedC, err: ErrMismatched, pos: 4},
+		{give: `(a[b]`, when: BalancedC, err: ErrUnclosed, pos: 0},
+		{give: `(a[b`, when: BalancedC, err: ErrUnclosed, pos: 2},
+		{give: `(")`, when: BalancedC, err: ErrUnclosed, pos: 1},
+		{give: `( /* )`, when: BalancedC, err: ErrUnclosed, pos: 2},
+		{give: `x()`, when: BalancedC, then: false},
+		{give: ``, when: BalancedC, then: false},
+		{give: `[[1]]`, when: Balanced{Brackets: BalancedJSON.Brackets, MaxDepth: 2}, then: true, exp: `[[1]]`},
+		{give: `[[[1]]]`, when: Balanced{Brackets: BalancedJSON.Brackets, MaxDepth: 2}, err: ErrTooDeep, pos: 2},
+		{give: `begin if end end.`, when: Balanced{Brackets: []Pair{{"begin", "end"}, {"if", "end"}}}, then: true, exp: `begin if end end`},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		m := s.Mark()
+		ok, err := s.UtilMatchBalanced(&tc.when)
+		assertEqual(t, tc.then, ok, tc.give)
+		assertEqual(t, tc.exp, s.Token(m), tc.give)
+		if tc.err == nil {
+			assertEqual(t, nil, err, tc.give)
+			continue
+		}
+		var e *BalanceError
+		assertEqual(t, true, errors.As(err, &e), tc.give)
+		assertEqual(t, tc.err, e.Err, tc.give)
+		assertEqual(t, tc.pos, e.Pos, tc.give)
+	}
+}
+
+func BenchmarkScannerUtilMatchBalanced(b *testing.B) {
+	x := Scanner(`{"a": [1, 2, {"b": "}"}]}`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilMatchBalanced(&BalancedJSON)
+	}
+}
+
+// #endregion Balanced
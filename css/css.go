@@ -0,0 +1,456 @@
+// Package css tokenizes CSS as in CSS Syntax Level 3
+// with the scanner, and parses selectors. Tokens and
+// selectors have spans, so the input can be rewritten,
+// like renaming class names. Unescaped values are
+// sub-slices of the input.
+package css
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ofabricio/scanner"
+)
+
+// #region Token
+
+// Kind is the kind of a Token.
+type Kind int
+
+const (
+	EOF Kind = iota
+	Ident
+	Function
+	AtKeyword
+	Hash
+	String
+	BadString
+	URL
+	BadURL
+	Delim
+	Number
+	Percentage
+	Dimension
+	Whitespace
+	Comment
+	CDO
+	CDC
+	Colon
+	Semicolon
+	Comma
+	LeftBracket
+	RightBracket
+	LeftParen
+	RightParen
+	LeftBrace
+	RightBrace
+)
+
+var kindNames = [...]string{
+	"EOF", "Ident", "Function", "AtKeyword", "Hash", "String", "BadString", "URL", "BadURL",
+	"Delim", "Number", "Percentage", "Dimension", "Whitespace", "Comment", "CDO", "CDC",
+	"Colon", "Semicolon", "Comma", "LeftBracket", "RightBracket", "LeftParen", "RightParen",
+	"LeftBrace", "RightBrace",
+}
+
+func (k Kind) String() string {
+	if k >= 0 && int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Token is a token of a style sheet.
+type Token struct {
+	Kind Kind
+	// Value is the unescaped name of an Ident, Function,
+	// AtKeyword or Hash, the value of a String or URL, the
+	// text of a Comment, the character of a Delim, and the
+	// number of a Number, Percentage or Dimension.
+	Value string
+	// Num is the value of a Number,
+	// Percentage or Dimension.
+	Num float64
+	// Int tells if a number is an integer.
+	Int bool
+	// Unit is the unescaped unit of a Dimension.
+	Unit string
+	// ID tells if a Hash is a valid ID, like #a but not #1.
+	ID   bool
+	Span scanner.Span
+}
+
+// #endregion Token
+
+// #region Tokenizer
+
+// Tokenizer reads the tokens of a style sheet.
+// It never fails: malformed input becomes
+// BadString, BadURL or Delim tokens.
+type Tokenizer struct {
+	src, s scanner.Scanner
+}
+
+// NewTokenizer returns a tokenizer of src.
+func NewTokenizer(src string) *Tokenizer {
+	s := scanner.Scanner(src)
+	return &Tokenizer{src: s, s: s}
+}
+
+// Tokenize returns the tokens of src, without EOF.
+func Tokenize(src string) []Token {
+	var list []Token
+	t := NewTokenizer(src)
+	for tok := t.Next(); tok.Kind != EOF; tok = t.Next() {
+		list = append(list, tok)
+	}
+	return list
+}
+
+// Next returns the next token, or EOF at the end.
+func (t *Tokenizer) Next() Token {
+	at := t.s
+	tok := t.next()
+	tok.Span = t.src.Span(at, t.s)
+	return tok
+}
+
+// punct are the single character tokens.
+var punct = [128]Kind{
+	':': Colon, ';': Semicolon, ',': Comma, '[': LeftBracket, ']': RightBracket,
+	'(': LeftParen, ')': RightParen, '{': LeftBrace, '}': RightBrace,
+}
+
+func (t *Tokenizer) next() Token {
+	if !t.s.More() {
+		return Token{Kind: EOF}
+	}
+	switch c := t.s.Curr(); {
+	case t.s.Equal("/*"):
+		t.s.Advance(2)
+		m := t.s
+		if !t.s.MatchUntil("*/") {
+			t.s.Advance(len(t.s))
+		}
+		v := t.s.Token(m)
+		t.s.Match("*/")
+		return Token{Kind: Comment, Value: v}
+	case isSpace(c):
+		t.s.MatchWhileByteBy(isSpace)
+		return Token{Kind: Whitespace}
+	case c == '"' || c == '\'':
+		return t.string(c)
+	case c == '#' && (len(t.s) > 1 && isName(t.s[1]) || validEscape(t.s[1:])):
+		t.s.Next()
+		id := startsIdent(t.s)
+		return Token{Kind: Hash, Value: t.name(), ID: id}
+	case startsNumber(t.s):
+		return t.number()
+	case t.s.Match("<!--"):
+		return Token{Kind: CDO}
+	case t.s.Match("-->"):
+		return Token{Kind: CDC}
+	case startsIdent(t.s):
+		return t.identLike()
+	case c == '@' && startsIdent(t.s[1:]):
+		t.s.Next()
+		return Token{Kind: AtKeyword, Value: t.name()}
+	case c < utf8.RuneSelf && punct[c] != 0:
+		t.s.Next()
+		return Token{Kind: punct[c]}
+	}
+	m := t.s
+	t.s.NextRune()
+	return Token{Kind: Delim, Value: t.s.Token(m)}
+}
+
+// identLike reads an Ident, a Function or a URL.
+func (t *Tokenizer) identLike() Token {
+	name := t.name()
+	if !t.s.MatchByte('(') {
+		return Token{Kind: Ident, Value: name}
+	}
+	if strings.EqualFold(name, "url") {
+		s := t.s
+		s.MatchWhileByteBy(isSpace)
+		if !s.EqualByte('"') && !s.EqualByte('\'') {
+			return t.url()
+		}
+	}
+	return Token{Kind: Function, Value: name}
+}
+
+// url reads an unquoted url(), like url(a.png).
+func (t *Tokenizer) url() Token {
+	t.s.MatchWhileByteBy(isSpace)
+	var b []byte
+	m := t.s
+	for {
+		c := t.s.Curr()
+		switch {
+		case !t.s.More():
+			return Token{Kind: URL, Value: value(b, m, t.s)}
+		case c == ')':
+			v := value(b, m, t.s)
+			t.s.Next()
+			return Token{Kind: URL, Value: v}
+		case isSpace(c):
+			end := t.s
+			t.s.MatchWhileByteBy(isSpace)
+			if !t.s.More() || t.s.MatchByte(')') {
+				return Token{Kind: URL, Value: value(b, m, end)}
+			}
+			return t.badURL()
+		case c == '"' || c == '\'' || c == '(' || isNonPrintable(c):
+			return t.badURL()
+		case c == '\\':
+			if !validEscape(t.s) {
+				return t.badURL()
+			}
+			if b == nil {
+				b = append([]byte{}, t.s.Token(m)...)
+			}
+			b = t.escape(b)
+		default:
+			if b != nil {
+				b = append(b, c)
+			}
+			t.s.Next()
+		}
+	}
+}
+
+// badURL skips the rest of a bad url().
+func (t *Tokenizer) badURL() Token {
+	for t.s.More() && !t.s.MatchByte(')') {
+		if validEscape(t.s) {
+			t.escape(nil)
+			continue
+		}
+		t.s.Next()
+	}
+	return Token{Kind: BadURL}
+}
+
+// string reads a quoted string. A line break
+// in it makes a BadString, without the break.
+func (t *Tokenizer) string(q byte) Token {
+	t.s.Next()
+	var b []byte
+	m := t.s
+	for {
+		c := t.s.Curr()
+		switch {
+		case !t.s.More():
+			return Token{Kind: String, Value: value(b, m, t.s)}
+		case c == q:
+			v := value(b, m, t.s)
+			t.s.Next()
+			return Token{Kind: String, Value: v}
+		case c == '\n' || c == '\r' || c == '\f':
+			return Token{Kind: BadString}
+		case c == '\\':
+			if b == nil {
+				b = append([]byte{}, t.s.Token(m)...)
+			}
+			// An escaped line break continues the string.
+			if s := t.s[1:]; s.Match("\r\n") || s.MatchByte('\n') || s.MatchByte('\r') || s.MatchByte('\f') || !s.More() {
+				t.s = s
+				continue
+			}
+			b = t.escape(b)
+		default:
+			if b != nil {
+				b = append(b, c)
+			}
+			t.s.Next()
+		}
+	}
+}
+
+// number reads a Number, a Percentage or a Dimension.
+func (t *Tokenizer) number() Token {
+	m := t.s
+	if !t.s.MatchByte('+') {
+		t.s.MatchByte('-')
+	}
+	t.s.MatchWhileByteBy(isDigit)
+	tok := Token{Kind: Number, Int: true}
+	if len(t.s) > 1 && t.s[0] == '.' && isDigit(t.s[1]) {
+		t.s.Next()
+		t.s.MatchWhileByteBy(isDigit)
+		tok.Int = false
+	}
+	if c := t.s.Curr(); c == 'e' || c == 'E' {
+		s := t.s
+		s.Next()
+		if !s.MatchByte('+') {
+			s.MatchByte('-')
+		}
+		if s.More() && isDigit(s.Curr()) {
+			t.s = s
+			t.s.MatchWhileByteBy(isDigit)
+			tok.Int = false
+		}
+	}
+	tok.Value = t.s.Token(m)
+	tok.Num = numValue(tok.Value)
+	switch {
+	case startsIdent(t.s):
+		tok.Kind, tok.Unit = Dimension, t.name()
+	case t.s.MatchByte('%'):
+		tok.Kind = Percentage
+	}
+	return tok
+}
+
+// numValue returns the value of a number, like +007 or -.5e3.
+// The sign, leading zeros and a bare dot, which a JSON number
+// doesn't take, are dealt with before UtilParseFloat.
+func numValue(v string) float64 {
+	s := scanner.Scanner(v)
+	neg := s.MatchByte('-')
+	if !neg {
+		s.MatchByte('+')
+	}
+	for len(s) > 1 && s[0] == '0' && isDigit(s[1]) {
+		s.Next()
+	}
+	if s.EqualByte('.') {
+		// A short fraction, like .5, is exact as its digits
+		// over a power of ten, so it needs no "0" in front.
+		d, n := s[1:], len(s)-1
+		if u, err := d.UtilParseUint(); err == nil && !d.More() && n <= 15 {
+			f := float64(u) / math.Pow10(n)
+			if neg {
+				return -f
+			}
+			return f
+		}
+		s = "0" + s
+	}
+	f, err := s.UtilParseFloat()
+	if err != nil {
+		// The number is valid, so it is out of range.
+		f = math.Inf(1)
+	}
+	if neg {
+		return -f
+	}
+	return f
+}
+
+// name reads a name with escapes.
+func (t *Tokenizer) name() string {
+	var b []byte
+	m := t.s
+	for t.s.More() {
+		if c := t.s.Curr(); isName(c) {
+			if b != nil {
+				b = append(b, c)
+			}
+			t.s.Next()
+			continue
+		}
+		if !validEscape(t.s) {
+			break
+		}
+		if b == nil {
+			b = append([]byte{}, t.s.Token(m)...)
+		}
+		b = t.escape(b)
+	}
+	return value(b, m, t.s)
+}
+
+// escape decodes the escape at the scanner into b, like
+// \41 or \". Invalid code points are U+FFFD.
+func (t *Tokenizer) escape(b []byte) []byte {
+	t.s.Next()
+	if !t.s.More() {
+		return utf8.AppendRune(b, utf8.RuneError)
+	}
+	n := 0
+	for n < 6 && n < len(t.s) && isHex(t.s[n]) {
+		n++
+	}
+	if v, ok := t.s.UtilParseHex(n); ok {
+		if !t.s.Match("\r\n") && t.s.More() && isSpace(t.s.Curr()) {
+			t.s.Next()
+		}
+		r := rune(v)
+		if r == 0 || !utf8.ValidRune(r) {
+			r = utf8.RuneError
+		}
+		return utf8.AppendRune(b, r)
+	}
+	m := t.s
+	t.s.NextRune()
+	return append(b, t.s.Token(m)...)
+}
+
+// value returns b, or the text between
+// m and end if there were no escapes.
+func value(b []byte, m, end scanner.Scanner) string {
+	if b == nil {
+		return end.Token(m)
+	}
+	return string(b)
+}
+
+// validEscape tells if s starts with an escape.
+func validEscape(s scanner.Scanner) bool {
+	return len(s) > 1 && s[0] == '\\' && s[1] != '\n' && s[1] != '\r' && s[1] != '\f'
+}
+
+// startsIdent tells if s starts with an identifier.
+func startsIdent(s scanner.Scanner) bool {
+	if len(s) == 0 {
+		return false
+	}
+	if s[0] == '-' {
+		return len(s) > 1 && (isNameStart(s[1]) || s[1] == '-' || validEscape(s[1:]))
+	}
+	return isNameStart(s[0]) || validEscape(s)
+}
+
+// startsNumber tells if s starts with a number.
+func startsNumber(s scanner.Scanner) bool {
+	if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+		s = s[1:]
+	}
+	if len(s) > 1 && s[0] == '.' {
+		s = s[1:]
+	}
+	return len(s) > 0 && isDigit(s[0])
+}
+
+// isNameStart tells if c starts a name. Bytes of
+// non-ASCII characters are name characters.
+func isNameStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c >= utf8.RuneSelf
+}
+
+func isName(c byte) bool {
+	return isNameStart(c) || isDigit(c) || c == '-'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHex(c byte) bool {
+	return isDigit(c) || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isNonPrintable(c byte) bool {
+	return c <= 0x08 || c == 0x0B || c >= 0x0E && c <= 0x1F || c == 0x7F
+}
+
+// #endregion Tokenizer
@@ -0,0 +1,160 @@
+package css
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// #region Tokenizer
+
+func TestTokenize(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+	}{
+		{give: "a{color:red}", exp: `Ident(a) LeftBrace Ident(color) Colon Ident(red) RightBrace`},
+		{give: "-webkit-box --x _a é", exp: `Ident(-webkit-box) Whitespace Ident(--x) Whitespace Ident(_a) Whitespace Ident(é)`},
+		{give: "@media @-moz-doc @ @1", exp: `AtKeyword(media) Whitespace AtKeyword(-moz-doc) Whitespace Delim(@) Whitespace Delim(@) Number(1 int)`},
+		{give: "#fff #1a #-x", exp: `Hash(fff id) Whitespace Hash(1a) Whitespace Hash(-x id)`},
+		{give: `"a'b" 'c"d' "e\"f"`, exp: `String(a'b) Whitespace String(c"d) Whitespace String(e"f)`},
+		{give: "\"a\\\nb\" \"c", exp: `String(ab) Whitespace String(c)`},
+		{give: "\"a\nb", exp: `BadString Whitespace Ident(b)`},
+		{give: "1 -2 +3.5 .5 1e3 1.5E-2", exp: `Number(1 int) Whitespace Number(-2 int) Whitespace Number(+3.5) Whitespace Number(.5) Whitespace Number(1e3) Whitespace Number(1.5E-2)`},
+		{give: "10px 1.5em 50% -2deg 1e 3.", exp: `Dimension(10 px) Whitespace Dimension(1.5 em) Whitespace Percentage(50) Whitespace Dimension(-2 deg) Whitespace Dimension(1 e) Whitespace Number(3 int) Delim(.)`},
+		{give: "url(a.png) URL( b.png ) url('d.png')", exp: `URL(a.png) Whitespace URL(b.png) Whitespace Function(url) String(d.png) RightParen`},
+		{give: "url( a b ) url(a\"b) url(a(b)", exp: `BadURL Whitespace BadURL Whitespace BadURL`},
+		{give: "url(a\\)b)", exp: `URL(a)b)`},
+		{give: "rgb(1,2)", exp: `Function(rgb) Number(1 int) Comma Number(2 int) RightParen`},
+		{give: "/* a */b/* c", exp: `Comment( a ) Ident(b) Comment( c)`},
+		{give: "<!-- a -->", exp: `CDO Whitespace Ident(a) Whitespace CDC`},
+		{give: "a;b,[c] > ~ + * . \\\n", exp: `Ident(a) Semicolon Ident(b) Comma LeftBracket Ident(c) RightBracket Whitespace Delim(>) Whitespace Delim(~) Whitespace Delim(+) Whitespace Delim(*) Whitespace Delim(.) Whitespace Delim(\) Whitespace`},
+	}
+	for _, tc := range tt {
+		assertEqual(t, tc.exp, tokens(tc.give), tc.give)
+	}
+}
+
+func TestTokenizeEscape(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+	}{
+		{give: `\41 b`, exp: "Ab"},
+		{give: `\000041b`, exp: "Ab"},
+		{give: `a\.b`, exp: "a.b"},
+		{give: `\31 0`, exp: "10"},
+		{give: `\0 \D800 \110000`, exp: "\uFFFD\uFFFD\uFFFD"},
+		{give: "\\é", exp: "é"},
+		{give: "\\41\r\nb", exp: "Ab"},
+	}
+	for _, tc := range tt {
+		toks := Tokenize(tc.give)
+		if assertEqual(t, 1, len(toks), tc.give) {
+			assertEqual(t, Ident, toks[0].Kind, tc.give)
+			assertEqual(t, tc.exp, toks[0].Value, tc.give)
+		}
+	}
+	toks := Tokenize(`"\41\"`)
+	assertEqual(t, "A\"", toks[0].Value)
+	toks = Tokenize(`"a\`)
+	assertEqual(t, "a", toks[0].Value)
+}
+
+func TestTokenizeSpan(t *testing.T) {
+	src := `.a{b:url(x) 10px "s"}`
+	var got []string
+	for _, tok := range Tokenize(src) {
+		got = append(got, tok.Span.Text(src))
+	}
+	assertEqual(t, []string{".", "a", "{", "b", ":", "url(x)", " ", "10px", " ", `"s"`, "}"}, got)
+}
+
+func TestTokenizeNumber(t *testing.T) {
+	toks := Tokenize("1.5em 50% 7")
+	assertEqual(t, 1.5, toks[0].Num)
+	assertEqual(t, "em", toks[0].Unit)
+	assertEqual(t, false, toks[0].Int)
+	assertEqual(t, 50.0, toks[2].Num)
+	assertEqual(t, 7.0, toks[4].Num)
+	assertEqual(t, true, toks[4].Int)
+
+	tt := []struct {
+		give string
+		exp  float64
+	}{
+		{give: "+007", exp: 7},
+		{give: "-.5e1", exp: -5},
+		{give: "00.25", exp: 0.25},
+		{give: ".25", exp: 0.25},
+		{give: "-.05", exp: -0.05},
+		{give: ".1234567890123456789", exp: 0.1234567890123456789},
+		{give: "1e-2", exp: 0.01},
+		{give: "-0", exp: 0},
+		{give: "1e999", exp: math.Inf(1)},
+	}
+	for _, tc := range tt {
+		assertEqual(t, tc.exp, Tokenize(tc.give)[0].Num, tc.give)
+	}
+}
+
+func TestTokenizeAllocs(t *testing.T) {
+	src := `.a > b:hover { color: #fff; margin: 1px 2em; background: url(a.png) }`
+	n := testing.AllocsPerRun(10, func() {
+		z := NewTokenizer(src)
+		for z.Next().Kind != EOF {
+		}
+	})
+	assertEqual(t, 0.0, n)
+}
+
+func BenchmarkTokenizer(b *testing.B) {
+	src := strings.Repeat(`.nav > li:not(.active) a[href^="http"] { color: #333; margin: 0 1.5em; background: url(img/bg.png) no-repeat; } /* x */ @media (max-width: 600px) { .a { width: 100%; } }`, 10)
+	b.SetBytes(int64(len(src)))
+	for i := 0; i < b.N; i++ {
+		z := NewTokenizer(src)
+		for z.Next().Kind != EOF {
+		}
+	}
+}
+
+// #endregion Tokenizer
+
+// tokens prints the tokens of src.
+func tokens(src string) string {
+	var out []string
+	for _, tok := range Tokenize(src) {
+		s := tok.Kind.String()
+		switch tok.Kind {
+		case Number:
+			if tok.Int {
+				s += "(" + tok.Value + " int)"
+				break
+			}
+			s += "(" + tok.Value + ")"
+		case Dimension:
+			s += "(" + tok.Value + " " + tok.Unit + ")"
+		case Hash:
+			if tok.ID {
+				s += "(" + tok.Value + " id)"
+				break
+			}
+			s += "(" + tok.Value + ")"
+		case Ident, Function, AtKeyword, String, URL, Delim, Comment, Percentage:
+			s += "(" + tok.Value + ")"
+		}
+		out = append(out, s)
+	}
+	return strings.Join(out, " ")
+}
+
+func assertEqual(t *testing.T, exp, got any, msgs ...any) bool {
+	t.Helper()
+	if !reflect.DeepEqual(exp, got) {
+		t.Errorf("\nExp:\n%v\nGot:\n%v\nMsg: %v", exp, got, fmt.Sprint(msgs...))
+		return false
+	}
+	return true
+}
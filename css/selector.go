@@ -0,0 +1,390 @@
+package css
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/ofabricio/scanner"
+)
+
+// #region Errors
+
+var (
+	ErrSelector = errors.New("expected a selector")
+	ErrName     = errors.New("expected a name")
+	ErrAttr     = errors.New("malformed attribute selector")
+	ErrParen    = errors.New("expected ')'")
+	ErrTrailing = errors.New("unexpected token")
+)
+
+// ParseError is a positioned error.
+type ParseError = scanner.ParseError
+
+// #endregion Errors
+
+// #region Selector
+
+// Selector is a complex selector, like "ul > li.a".
+type Selector struct {
+	Compounds []*Compound
+	// Combinators are between the compounds:
+	// ' ', '>', '+' or '~'.
+	Combinators []byte
+	// Leading is the combinator before a relative
+	// selector of :has(), like '>' in :has(> a).
+	Leading byte
+	Span    scanner.Span
+}
+
+// Compound is a compound selector, like "li.a:hover".
+type Compound struct {
+	Simple []*Simple
+	Span   scanner.Span
+}
+
+// SimpleKind is the kind of a Simple selector.
+type SimpleKind int
+
+const (
+	TypeSelector SimpleKind = iota + 1
+	UniversalSelector
+	IDSelector
+	ClassSelector
+	AttributeSelector
+	PseudoClass
+	PseudoElement
+)
+
+func (k SimpleKind) String() string {
+	switch k {
+	case TypeSelector:
+		return "Type"
+	case UniversalSelector:
+		return "Universal"
+	case IDSelector:
+		return "ID"
+	case ClassSelector:
+		return "Class"
+	case AttributeSelector:
+		return "Attribute"
+	case PseudoClass:
+		return "PseudoClass"
+	case PseudoElement:
+		return "PseudoElement"
+	}
+	return "SimpleKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Simple is a simple selector, like ".a" or "[href^='http']".
+type Simple struct {
+	Kind SimpleKind
+	// Name is the unescaped name of the element, ID,
+	// class, attribute or pseudo-class or element.
+	Name string
+	// Op is the operator of an attribute selector, like
+	// "=" or "^=", Value its value and Modifier its 'i'
+	// or 's' flag. Op is empty in [name].
+	Op       string
+	Value    string
+	Modifier byte
+	// Args is the text of the arguments of a
+	// functional pseudo-class, like "2n+1".
+	Args string
+	// Selectors are the arguments of
+	// :is(), :not(), :where() and :has().
+	Selectors []*Selector
+	// Function tells if a pseudo has arguments.
+	Function bool
+	// NameSpan is the span of the name as in the input,
+	// escapes included. It is where to rename a class.
+	NameSpan scanner.Span
+	Span     scanner.Span
+}
+
+// selectorArgs are the pseudo-classes whose
+// arguments are a list of selectors.
+var selectorArgs = map[string]bool{"is": true, "not": true, "where": true, "has": true, "matches": true}
+
+// ParseSelectors parses a comma-separated selector list.
+func ParseSelectors(src string) ([]*Selector, error) {
+	p := &parser{src: scanner.Scanner(src), end: len(src)}
+	for _, tok := range Tokenize(src) {
+		if tok.Kind != Comment {
+			p.toks = append(p.toks, tok)
+		}
+	}
+	list, err := p.list(false)
+	if err != nil {
+		return nil, err
+	}
+	if p.ws(); p.peek().Kind != EOF {
+		return nil, p.errorf(p.peek(), ErrTrailing)
+	}
+	return list, nil
+}
+
+type parser struct {
+	src  scanner.Scanner
+	toks []Token
+	i    int
+	end  int // Offset of the EOF.
+}
+
+// list parses selectors separated by commas. Relative
+// selectors can start with a combinator.
+func (p *parser) list(relative bool) ([]*Selector, error) {
+	var list []*Selector
+	for {
+		p.ws()
+		var lead Token
+		if tok := p.peek(); relative && isDelim(tok, ">+~") {
+			lead = p.next()
+			p.ws()
+		}
+		sel, err := p.selector()
+		if err != nil {
+			return nil, err
+		}
+		if lead.Kind == Delim {
+			sel.Leading = lead.Value[0]
+			sel.Span = sel.Span.Merge(lead.Span)
+		}
+		list = append(list, sel)
+		if p.ws(); p.peek().Kind != Comma {
+			return list, nil
+		}
+		p.i++
+	}
+}
+
+// selector parses compounds and combinators.
+func (p *parser) selector() (*Selector, error) {
+	sel := &Selector{}
+	for {
+		c, err := p.compound()
+		if err != nil {
+			return nil, err
+		}
+		if len(sel.Compounds) == 0 {
+			sel.Span = c.Span
+		}
+		sel.Compounds = append(sel.Compounds, c)
+		sel.Span = sel.Span.Merge(c.Span)
+		m := p.i
+		ws := p.ws()
+		comb := byte(' ')
+		if tok := p.peek(); isDelim(tok, ">+~") {
+			comb = tok.Value[0]
+			p.i++
+			p.ws()
+		} else if !ws || !p.startsCompound() {
+			p.i = m
+			return sel, nil
+		}
+		sel.Combinators = append(sel.Combinators, comb)
+	}
+}
+
+// startsCompound tells if the next token starts a compound.
+func (p *parser) startsCompound() bool {
+	switch tok := p.peek(); tok.Kind {
+	case Ident, Hash, LeftBracket, Colon:
+		return true
+	case Delim:
+		return tok.Value == "*" || tok.Value == "."
+	}
+	return false
+}
+
+// compound parses simple selectors with no whitespaces
+// between them. A type or '*' can only be the first.
+func (p *parser) compound() (*Compound, error) {
+	c := &Compound{}
+	for {
+		tok := p.peek()
+		var s *Simple
+		var err error
+		switch {
+		case len(c.Simple) == 0 && (tok.Kind == Ident || tok.Kind == Delim && tok.Value == "*"):
+			p.i++
+			s = &Simple{Kind: TypeSelector, Name: tok.Value, NameSpan: tok.Span, Span: tok.Span}
+			if tok.Kind == Delim {
+				s.Kind = UniversalSelector
+			}
+		case tok.Kind == Hash:
+			if !tok.ID {
+				return nil, p.errorf(tok, ErrName)
+			}
+			p.i++
+			name := tok.Span
+			name.Start++
+			s = &Simple{Kind: IDSelector, Name: tok.Value, NameSpan: name, Span: tok.Span}
+		case tok.Kind == Delim && tok.Value == ".":
+			p.i++
+			name := p.peek()
+			if name.Kind != Ident {
+				return nil, p.errorf(name, ErrName)
+			}
+			p.i++
+			s = &Simple{Kind: ClassSelector, Name: name.Value, NameSpan: name.Span, Span: tok.Span.Merge(name.Span)}
+		case tok.Kind == LeftBracket:
+			s, err = p.attribute()
+		case tok.Kind == Colon:
+			s, err = p.pseudo()
+		default:
+			if len(c.Simple) == 0 {
+				return nil, p.errorf(tok, ErrSelector)
+			}
+			return c, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(c.Simple) == 0 {
+			c.Span = s.Span
+		}
+		c.Simple = append(c.Simple, s)
+		c.Span = c.Span.Merge(s.Span)
+	}
+}
+
+// attribute parses an attribute selector,
+// like [a], [a=b] or [a^="b" i].
+func (p *parser) attribute() (*Simple, error) {
+	open := p.next()
+	p.ws()
+	name := p.next()
+	if name.Kind != Ident {
+		return nil, p.errorf(name, ErrAttr)
+	}
+	s := &Simple{Kind: AttributeSelector, Name: name.Value, NameSpan: name.Span}
+	p.ws()
+	if tok := p.peek(); tok.Kind == Delim {
+		p.i++
+		s.Op = tok.Value
+		if tok.Value != "=" {
+			eq := p.next()
+			if !isDelim(tok, "~|^$*") || !isDelim(eq, "=") || eq.Span.Start != tok.Span.End {
+				return nil, p.errorf(tok, ErrAttr)
+			}
+			s.Op += "="
+		}
+		p.ws()
+		v := p.next()
+		if v.Kind != Ident && v.Kind != String {
+			return nil, p.errorf(v, ErrAttr)
+		}
+		s.Value = v.Value
+		p.ws()
+		if tok := p.peek(); tok.Kind == Ident && (strings.EqualFold(tok.Value, "i") || strings.EqualFold(tok.Value, "s")) {
+			p.i++
+			s.Modifier = tok.Value[0] | 0x20
+			p.ws()
+		}
+	}
+	end := p.next()
+	if end.Kind != RightBracket {
+		return nil, p.errorf(end, ErrAttr)
+	}
+	s.Span = open.Span.Merge(end.Span)
+	return s, nil
+}
+
+// pseudo parses a pseudo-class or a pseudo-element, like
+// :hover, :nth-child(2n+1), :not(.a) or ::before.
+func (p *parser) pseudo() (*Simple, error) {
+	colon := p.next()
+	s := &Simple{Kind: PseudoClass}
+	if tok := p.peek(); tok.Kind == Colon {
+		p.i++
+		s.Kind = PseudoElement
+	}
+	name := p.next()
+	s.Name = strings.ToLower(name.Value)
+	s.NameSpan = name.Span
+	switch name.Kind {
+	case Ident:
+		s.Span = colon.Span.Merge(name.Span)
+		return s, nil
+	case Function:
+		s.NameSpan.End-- // The '('.
+	default:
+		return nil, p.errorf(name, ErrName)
+	}
+	// The arguments, up to the matching ')'.
+	s.Function = true
+	ini, depth := p.i, 0
+	for ; ; p.i++ {
+		tok := p.peek()
+		switch tok.Kind {
+		case EOF:
+			return nil, p.errorf(tok, ErrParen)
+		case Function, LeftParen:
+			depth++
+			continue
+		case RightParen:
+			if depth--; depth >= 0 {
+				continue
+			}
+		default:
+			continue
+		}
+		break
+	}
+	args := p.toks[ini:p.i]
+	end := p.next()
+	s.Span = colon.Span.Merge(end.Span)
+	s.Args = strings.TrimSpace(p.src[name.Span.End:end.Span.Start].String())
+	if selectorArgs[s.Name] && s.Kind == PseudoClass {
+		sub := &parser{src: p.src, toks: args, end: end.Span.Start}
+		list, err := sub.list(s.Name == "has")
+		if err != nil {
+			return nil, err
+		}
+		if sub.ws(); sub.peek().Kind != EOF {
+			return nil, sub.errorf(sub.peek(), ErrTrailing)
+		}
+		s.Selectors = list
+	}
+	return s, nil
+}
+
+// peek returns the next token, or EOF
+// at the end of the input or of the
+// arguments of a pseudo-class.
+func (p *parser) peek() Token {
+	if p.i < len(p.toks) {
+		return p.toks[p.i]
+	}
+	return Token{Kind: EOF, Span: scanner.Span{Start: p.end, End: p.end}}
+}
+
+// isDelim tells if tok is a Delim of one of chars.
+func isDelim(tok Token, chars string) bool {
+	return tok.Kind == Delim && len(tok.Value) == 1 && strings.IndexByte(chars, tok.Value[0]) >= 0
+}
+
+func (p *parser) next() Token {
+	tok := p.peek()
+	if p.i < len(p.toks) {
+		p.i++
+	}
+	return tok
+}
+
+// ws skips whitespaces and tells if there were any.
+func (p *parser) ws() bool {
+	m := p.i
+	for p.peek().Kind == Whitespace {
+		p.i++
+	}
+	return p.i > m
+}
+
+func (p *parser) errorf(at Token, err error) error {
+	line, col := p.src.LineCol(at.Span.Start)
+	return &ParseError{Line: line, Col: col, Err: err, Pkg: "css"}
+}
+
+// #endregion Selector
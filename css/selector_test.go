@@ -0,0 +1,173 @@
+package css
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// #region Selector
+
+func TestParseSelectors(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+	}{
+		{give: "a", exp: "Type(a)"},
+		{give: "*", exp: "Universal(*)"},
+		{give: "li.a.b#c", exp: "Type(li).Class(a).Class(b).ID(c)"},
+		{give: ".a .b", exp: "Class(a) ' ' Class(b)"},
+		{give: "ul > li + li ~ p", exp: "Type(ul) '>' Type(li) '+' Type(li) '~' Type(p)"},
+		{give: "a>b", exp: "Type(a) '>' Type(b)"},
+		{give: " a , b ", exp: "Type(a), Type(b)"},
+		{give: "a /* x */ b", exp: "Type(a) ' ' Type(b)"},
+		{give: "[href]", exp: "Attribute(href)"},
+		{give: `a[href^="http"][lang|=en][ data-x = 'y' i ]`, exp: "Type(a).Attribute(href^=http).Attribute(lang|=en).Attribute(data-x=y i)"},
+		{give: "[a~=b s][a$=b][a*=b]", exp: "Attribute(a~=b s).Attribute(a$=b).Attribute(a*=b)"},
+		{give: "a:hover::before", exp: "Type(a).PseudoClass(hover).PseudoElement(before)"},
+		{give: "li:nth-child( 2n+1 )", exp: "Type(li).PseudoClass(nth-child 2n+1)"},
+		{give: ":lang(en):nth-of-type(odd)", exp: "PseudoClass(lang en).PseudoClass(nth-of-type odd)"},
+		{give: "a:not(.b, [c])", exp: "Type(a).PseudoClass(not [Class(b), Attribute(c)])"},
+		{give: ":is(ul, ol) > li:has(> a)", exp: "PseudoClass(is [Type(ul), Type(ol)]) '>' Type(li).PseudoClass(has ['>' Type(a)])"},
+		{give: "a:has(+ b, c ~ d)", exp: "Type(a).PseudoClass(has ['+' Type(b), Type(c) '~' Type(d)])"},
+		{give: ":where(:not(a b))", exp: "PseudoClass(where [PseudoClass(not [Type(a) ' ' Type(b)])])"},
+		{give: `.\31 0`, exp: "Class(10)"},
+		{give: ":HOVER", exp: "PseudoClass(hover)"},
+	}
+	for _, tc := range tt {
+		list, err := ParseSelectors(tc.give)
+		if assertEqual(t, nil, err, tc.give) {
+			assertEqual(t, tc.exp, selectors(list), tc.give)
+		}
+	}
+}
+
+func TestParseSelectorsSpan(t *testing.T) {
+	src := `ul > li.item:not(.x) , a[href]`
+	list, err := ParseSelectors(src)
+	assertEqual(t, nil, err)
+	assertEqual(t, "ul > li.item:not(.x)", list[0].Span.Text(src))
+	assertEqual(t, "li.item:not(.x)", list[0].Compounds[1].Span.Text(src))
+	class := list[0].Compounds[1].Simple[1]
+	assertEqual(t, ".item", class.Span.Text(src))
+	assertEqual(t, "item", class.NameSpan.Text(src))
+	not := list[0].Compounds[1].Simple[2]
+	assertEqual(t, ":not(.x)", not.Span.Text(src))
+	assertEqual(t, "not", not.NameSpan.Text(src))
+	assertEqual(t, "x", not.Selectors[0].Compounds[0].Simple[0].NameSpan.Text(src))
+	assertEqual(t, "a[href]", list[1].Span.Text(src))
+	assertEqual(t, "href", list[1].Compounds[0].Simple[1].NameSpan.Text(src))
+}
+
+func TestParseSelectorsRewrite(t *testing.T) {
+	// Prefixes the class names.
+	src := `.btn:hover, .nav > :is(.item, a.btn)`
+	list, err := ParseSelectors(src)
+	assertEqual(t, nil, err)
+	var spans []int
+	var walk func([]*Selector)
+	walk = func(list []*Selector) {
+		for _, sel := range list {
+			for _, c := range sel.Compounds {
+				for _, s := range c.Simple {
+					if s.Kind == ClassSelector {
+						spans = append(spans, s.NameSpan.Start)
+					}
+					walk(s.Selectors)
+				}
+			}
+		}
+	}
+	walk(list)
+	var b strings.Builder
+	last := 0
+	for _, off := range spans {
+		b.WriteString(src[last:off] + "x-")
+		last = off
+	}
+	b.WriteString(src[last:])
+	assertEqual(t, `.x-btn:hover, .x-nav > :is(.x-item, a.x-btn)`, b.String())
+}
+
+func TestParseSelectorsError(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+		err  error
+	}{
+		{give: "", exp: "css: expected a selector at 1:1", err: ErrSelector},
+		{give: "a,", exp: "css: expected a selector at 1:3", err: ErrSelector},
+		{give: "a >", exp: "css: expected a selector at 1:4", err: ErrSelector},
+		{give: "> a", exp: "css: expected a selector at 1:1", err: ErrSelector},
+		{give: "a b{", exp: "css: unexpected token at 1:4", err: ErrTrailing},
+		{give: ". a", exp: "css: expected a name at 1:2", err: ErrName},
+		{give: "#1a", exp: "css: expected a name at 1:1", err: ErrName},
+		{give: "a:1", exp: "css: expected a name at 1:3", err: ErrName},
+		{give: "[]", exp: "css: malformed attribute selector at 1:2", err: ErrAttr},
+		{give: "[a=]", exp: "css: malformed attribute selector at 1:4", err: ErrAttr},
+		{give: "[a!=b]", exp: "css: malformed attribute selector at 1:3", err: ErrAttr},
+		{give: "[a^ =b]", exp: "css: malformed attribute selector at 1:3", err: ErrAttr},
+		{give: "[a=b c]", exp: "css: malformed attribute selector at 1:6", err: ErrAttr},
+		{give: "[a", exp: "css: malformed attribute selector at 1:3", err: ErrAttr},
+		{give: ":not(a", exp: "css: expected ')' at 1:7", err: ErrParen},
+		{give: ":not(> a)", exp: "css: expected a selector at 1:6", err: ErrSelector},
+		{give: ":not()", exp: "css: expected a selector at 1:6", err: ErrSelector},
+		{give: ":not(a{)", exp: "css: unexpected token at 1:7", err: ErrTrailing},
+		{give: "a\n  b.", exp: "css: expected a name at 2:5", err: ErrName},
+	}
+	for _, tc := range tt {
+		_, err := ParseSelectors(tc.give)
+		if assertEqual(t, true, err != nil, tc.give) {
+			assertEqual(t, tc.exp, err.Error(), tc.give)
+			assertEqual(t, true, errors.Is(err, tc.err), tc.give)
+		}
+	}
+}
+
+func BenchmarkParseSelectors(b *testing.B) {
+	src := `.nav > li:not(.active, .disabled) a[href^="http" i]::after, ul li:nth-child(2n+1) ~ p#intro.lead`
+	for i := 0; i < b.N; i++ {
+		ParseSelectors(src)
+	}
+}
+
+// #endregion Selector
+
+// selectors prints a selector list.
+func selectors(list []*Selector) string {
+	var out []string
+	for _, sel := range list {
+		var s []string
+		if sel.Leading != 0 {
+			s = append(s, "'"+string(sel.Leading)+"'")
+		}
+		for i, c := range sel.Compounds {
+			if i > 0 {
+				s = append(s, "'"+string(sel.Combinators[i-1])+"'")
+			}
+			var simple []string
+			for _, v := range c.Simple {
+				simple = append(simple, simpleString(v))
+			}
+			s = append(s, strings.Join(simple, "."))
+		}
+		out = append(out, strings.Join(s, " "))
+	}
+	return strings.Join(out, ", ")
+}
+
+func simpleString(v *Simple) string {
+	s := v.Kind.String() + "(" + v.Name
+	switch {
+	case v.Op != "":
+		s += v.Op + v.Value
+		if v.Modifier != 0 {
+			s += " " + string(v.Modifier)
+		}
+	case v.Selectors != nil:
+		s += " [" + selectors(v.Selectors) + "]"
+	case v.Function:
+		s += " " + v.Args
+	}
+	return s + ")"
+}
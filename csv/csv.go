@@ -0,0 +1,306 @@
+// Package csv reads RFC 4180 comma-separated values
+// with the scanner. Unquoted fields are sub-slices of
+// the input, so reading them doesn't allocate.
+package csv
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/ofabricio/scanner"
+)
+
+// #region Dialect
+
+// Dialect is a CSV format.
+type Dialect struct {
+	// Delimiter separates the fields.
+	// Zero means a comma.
+	Delimiter byte
+	// Quote quotes the fields. A quote inside
+	// a quoted field is written twice.
+	// Zero disables quoting.
+	Quote byte
+	// Comment starts a comment line, like '#'.
+	// Zero disables comments.
+	Comment byte
+	// LazyQuotes accepts quotes inside unquoted fields.
+	LazyQuotes bool
+	// Header tells if the first record is a header.
+	// The reader returns it as any other record.
+	Header bool
+	// LineTerminator is "\n" or "\r\n". The reader
+	// takes both, so it only informs the caller.
+	LineTerminator string
+}
+
+// Dialects of some formats.
+var (
+	DialectRFC4180 = Dialect{Delimiter: ',', Quote: '"', LineTerminator: "\r\n"}
+	DialectTSV     = Dialect{Delimiter: '\t', LineTerminator: "\n"}
+)
+
+// #endregion Dialect
+
+// #region Errors
+
+var (
+	ErrBareQuote  = errors.New("bare quote in unquoted field")
+	ErrQuote      = errors.New("extraneous quote in quoted field")
+	ErrUnclosed   = errors.New("unclosed quote")
+	ErrFieldCount = errors.New("wrong number of fields")
+)
+
+// ParseError is a positioned error.
+type ParseError = scanner.ParseError
+
+// #endregion Errors
+
+// #region Field
+
+// Field is a field of a record.
+type Field struct {
+	// Raw is the field as in the input,
+	// quotes included.
+	Raw  string
+	Line int // 1-based line of the field.
+	Col  int // 1-based column of the field, in bytes.
+
+	quote   byte // Zero if unquoted.
+	escaped bool // Has doubled quotes.
+}
+
+// Quoted tells if the field is quoted.
+func (f Field) Quoted() bool {
+	return f.quote != 0
+}
+
+// Value returns the field unquoted. It is a sub-slice
+// of the input unless the field has doubled quotes.
+func (f Field) Value() string {
+	if f.quote == 0 {
+		return f.Raw
+	}
+	v := f.Raw[1 : len(f.Raw)-1]
+	if f.escaped {
+		q := string(f.quote)
+		v = strings.ReplaceAll(v, q+q, q)
+	}
+	return v
+}
+
+// #endregion Field
+
+// #region Reader
+
+// Ragged is what to do with a record whose
+// number of fields is not the expected one.
+type Ragged int
+
+const (
+	// RaggedError returns the record with ErrFieldCount.
+	RaggedError Ragged = iota
+	// RaggedAllow returns the record as is.
+	RaggedAllow
+	// RaggedFix pads short records with
+	// empty fields and truncates long ones.
+	RaggedFix
+)
+
+// Reader reads records of a CSV input.
+type Reader struct {
+	Dialect Dialect
+	// Fields is the expected number of fields per
+	// record. Zero takes it from the first record.
+	Fields int
+	// Ragged is the policy for records whose number
+	// of fields differs from Fields.
+	Ragged Ragged
+
+	s       scanner.Scanner
+	src     scanner.Scanner
+	line    int // Current line.
+	lineOff int // Offset of the current line.
+	fields  []Field
+}
+
+// NewReader returns a reader of src in a dialect.
+func NewReader(src string, d Dialect) *Reader {
+	if d.Delimiter == 0 {
+		d.Delimiter = ','
+	}
+	s := scanner.Scanner(src)
+	return &Reader{Dialect: d, s: s, src: s, line: 1}
+}
+
+// Next returns the fields of the next record, skipping
+// empty and comment lines, or io.EOF at the end. The
+// slice is reused by the next call. On a syntax error
+// the rest of the line is skipped, so reading can go on.
+func (r *Reader) Next() ([]Field, error) {
+	r.fields = r.fields[:0]
+	for {
+		if !r.s.More() {
+			return nil, io.EOF
+		}
+		if c := r.s.Curr(); c == '\n' || c == '\r' && r.s.Equal("\r\n") ||
+			c == r.Dialect.Comment && c != 0 {
+			r.skipLine()
+			continue
+		}
+		break
+	}
+	line, col := r.line, r.col(r.s)
+	for {
+		f, err := r.field()
+		if err != nil {
+			r.skipLine()
+			return nil, err
+		}
+		r.fields = append(r.fields, f)
+		if !r.s.MatchByte(r.Dialect.Delimiter) {
+			break
+		}
+	}
+	if r.s.Match("\r\n") || r.s.MatchByte('\n') {
+		r.newline()
+	}
+	return r.check(line, col)
+}
+
+// Read returns the values of the next record.
+func (r *Reader) Read() ([]string, error) {
+	fields, err := r.Next()
+	if fields == nil {
+		return nil, err
+	}
+	rec := make([]string, len(fields))
+	for i, f := range fields {
+		rec[i] = f.Value()
+	}
+	return rec, err
+}
+
+// ReadAll returns the values of all the records.
+// It stops at the first error.
+func (r *Reader) ReadAll() ([][]string, error) {
+	var recs [][]string
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			return recs, nil
+		}
+		if err != nil {
+			return recs, err
+		}
+		recs = append(recs, rec)
+	}
+}
+
+// field scans a field up to the delimiter
+// or the line break, which are not consumed.
+func (r *Reader) field() (Field, error) {
+	d := r.Dialect
+	ini := r.s
+	f := Field{Line: r.line, Col: r.col(ini)}
+	if d.Quote != 0 && r.s.MatchByte(d.Quote) {
+		f.quote = d.Quote
+		for {
+			if !r.s.MatchUntilByte(d.Quote) {
+				return f, r.errorf(ini, ErrUnclosed)
+			}
+			r.s.Next()
+			if !r.s.MatchByte(d.Quote) {
+				break
+			}
+			f.escaped = true
+		}
+		f.Raw = r.s.Token(ini)
+		// Quoted fields may span lines.
+		if n := strings.LastIndexByte(f.Raw, '\n'); n >= 0 {
+			r.line += strings.Count(f.Raw, "\n")
+			r.lineOff = len(r.src) - len(ini) + n + 1
+		}
+		if r.s.More() && !r.s.EqualByte(d.Delimiter) && !r.s.EqualByte('\n') && !r.s.Equal("\r\n") {
+			return f, r.errorf(r.s, ErrQuote)
+		}
+		return f, nil
+	}
+	for {
+		// With no quote, a 0 would stop at NUL bytes.
+		var ok bool
+		if d.Quote == 0 {
+			ok = r.s.MatchUntilAnyByte(d.Delimiter, '\n')
+		} else {
+			ok = r.s.MatchUntilAnyByte3(d.Delimiter, '\n', d.Quote)
+		}
+		if !ok {
+			r.s.Advance(len(r.s))
+		}
+		if d.Quote == 0 || !r.s.EqualByte(d.Quote) {
+			break
+		}
+		if !d.LazyQuotes {
+			return f, r.errorf(r.s, ErrBareQuote)
+		}
+		r.s.Next()
+	}
+	f.Raw = r.s.Token(ini)
+	if strings.HasSuffix(f.Raw, "\r") && r.s.EqualByte('\n') {
+		f.Raw = f.Raw[:len(f.Raw)-1]
+		r.s = ini[len(f.Raw):]
+	}
+	return f, nil
+}
+
+// check applies the ragged policy to the record.
+func (r *Reader) check(line, col int) ([]Field, error) {
+	n := len(r.fields)
+	if r.Fields == 0 {
+		r.Fields = n
+	}
+	if n == r.Fields {
+		return r.fields, nil
+	}
+	switch r.Ragged {
+	case RaggedAllow:
+	case RaggedFix:
+		if n > r.Fields {
+			r.fields = r.fields[:r.Fields]
+		}
+		for last := r.fields[len(r.fields)-1]; len(r.fields) < r.Fields; {
+			r.fields = append(r.fields, Field{Line: last.Line, Col: last.Col + len(last.Raw)})
+		}
+	default:
+		return r.fields, &ParseError{Line: line, Col: col, Err: ErrFieldCount, Pkg: "csv"}
+	}
+	return r.fields, nil
+}
+
+// skipLine skips past the next line break.
+func (r *Reader) skipLine() {
+	if r.s.MatchUntilByte('\n') {
+		r.s.Next()
+		r.newline()
+		return
+	}
+	r.s.Advance(len(r.s))
+}
+
+// newline starts a line at the current position.
+func (r *Reader) newline() {
+	r.line++
+	r.lineOff = len(r.src) - len(r.s)
+}
+
+// col returns the column of a mark in the current line.
+func (r *Reader) col(at scanner.Scanner) int {
+	return len(r.src) - len(at) - r.lineOff + 1
+}
+
+func (r *Reader) errorf(at scanner.Scanner, err error) error {
+	return &ParseError{Line: r.line, Col: r.col(at), Err: err, Pkg: "csv"}
+}
+
+// #endregion Reader
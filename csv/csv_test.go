@@ -0,0 +1,147 @@
+package csv
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// #region Reader
+
+func TestReaderReadAll(t *testing.T) {
+	tt := []struct {
+		give string
+		when Dialect
+		then [][]string
+		exp  string // Error.
+	}{
+		{give: "a,b\nc,d\n", then: [][]string{{"a", "b"}, {"c", "d"}}},
+		{give: "a,b\r\nc,d", then: [][]string{{"a", "b"}, {"c", "d"}}},
+		{give: "a,b\n\n\r\nc,d\n", then: [][]string{{"a", "b"}, {"c", "d"}}},
+		{give: `"a,b","c""d",""`, then: [][]string{{"a,b", `c"d`, ""}}},
+		{give: "\"a\nb\",c\r\n", then: [][]string{{"a\nb", "c"}}},
+		{give: "a,,\n,,", then: [][]string{{"a", "", ""}, {"", "", ""}}},
+		{give: "# c\na;b\n#x;y", when: Dialect{Delimiter: ';', Quote: '"', Comment: '#'}, then: [][]string{{"a", "b"}}},
+		{give: "a\t\"b\"\n", when: DialectTSV, then: [][]string{{"a", `"b"`}}},
+		{give: "a\x00b\tc\n", when: DialectTSV, then: [][]string{{"a\x00b", "c"}}},
+		{give: "a\x00,b", when: Dialect{Delimiter: ','}, then: [][]string{{"a\x00", "b"}}},
+		{give: `a "b" c,d`, when: Dialect{Quote: '"', LazyQuotes: true}, then: [][]string{{`a "b" c`, "d"}}},
+		{give: "a,b\nc,d\"e\n", when: DialectRFC4180, then: [][]string{{"a", "b"}}, exp: `csv: bare quote in unquoted field at 2:4`},
+		{give: "a,\"b\"c\n", when: DialectRFC4180, exp: `csv: extraneous quote in quoted field at 1:6`},
+		{give: "a\n\"b\nc", when: DialectRFC4180, then: [][]string{{"a"}}, exp: `csv: unclosed quote at 2:1`},
+		{give: "a,b\nc\n", then: [][]string{{"a", "b"}}, exp: `csv: wrong number of fields at 2:1`},
+	}
+	for _, tc := range tt {
+		if tc.when == (Dialect{}) {
+			tc.when = DialectRFC4180
+		}
+		got, err := NewReader(tc.give, tc.when).ReadAll()
+		assertEqual(t, tc.then, got, tc.give)
+		if tc.exp == "" {
+			assertEqual(t, nil, err, tc.give)
+		} else if assertEqual(t, true, err != nil, tc.give) {
+			assertEqual(t, tc.exp, err.Error(), tc.give)
+		}
+	}
+}
+
+func TestReaderNext(t *testing.T) {
+	src := "a,\"b\"\r\n\"c\nd\",\"e\"\"\"\n"
+	r := NewReader(src, DialectRFC4180)
+
+	f, err := r.Next()
+	assertEqual(t, nil, err)
+	assertEqual(t, []Field{
+		{Raw: "a", Line: 1, Col: 1},
+		{Raw: `"b"`, Line: 1, Col: 3, quote: '"'},
+	}, f)
+
+	f, err = r.Next()
+	assertEqual(t, nil, err)
+	assertEqual(t, 2, len(f))
+	assertEqual(t, "c\nd", f[0].Value())
+	assertEqual(t, 2, f[0].Line)
+	assertEqual(t, `e"`, f[1].Value())
+	assertEqual(t, 3, f[1].Line)
+	assertEqual(t, 4, f[1].Col)
+	assertEqual(t, true, f[1].Quoted())
+
+	_, err = r.Next()
+	assertEqual(t, io.EOF, err)
+}
+
+func TestReaderNextAllocs(t *testing.T) {
+	r := NewReader(strings.Repeat("abc,\"d,e\"\n", 200), DialectRFC4180)
+	n := testing.AllocsPerRun(100, func() {
+		f, _ := r.Next()
+		for _, v := range f {
+			_ = v.Value()
+		}
+	})
+	assertEqual(t, 0.0, n)
+}
+
+func TestReaderRagged(t *testing.T) {
+	tt := []struct {
+		give string
+		when Ragged
+		then [][]string
+		exp  error
+	}{
+		{give: "a,b\nc\nd,e,f", when: RaggedAllow, then: [][]string{{"a", "b"}, {"c"}, {"d", "e", "f"}}},
+		{give: "a,b\nc\nd,e,f", when: RaggedFix, then: [][]string{{"a", "b"}, {"c", ""}, {"d", "e"}}},
+		{give: "a,b\nc\nd,e,f", when: RaggedError, then: [][]string{{"a", "b"}, {"c"}, {"d", "e", "f"}}, exp: ErrFieldCount},
+	}
+	for _, tc := range tt {
+		r := NewReader(tc.give, DialectRFC4180)
+		r.Ragged = tc.when
+		var got [][]string
+		for {
+			rec, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			if tc.exp != nil {
+				assertEqual(t, len(got) > 0, errors.Is(err, tc.exp), tc.give)
+			}
+			got = append(got, rec)
+		}
+		assertEqual(t, tc.then, got, tc.give)
+	}
+}
+
+func TestReaderFields(t *testing.T) {
+	r := NewReader("a,b\n", DialectRFC4180)
+	r.Fields = 3
+	_, err := r.Next()
+	var pe *ParseError
+	assertEqual(t, true, errors.As(err, &pe))
+	assertEqual(t, ParseError{Line: 1, Col: 1, Err: ErrFieldCount, Pkg: "csv"}, *pe)
+}
+
+func BenchmarkReaderNext(b *testing.B) {
+	src := strings.Repeat("abc,123,\"x,y\",\"q\"\"q\",z\r\n", 100)
+	b.SetBytes(int64(len(src)))
+	for i := 0; i < b.N; i++ {
+		r := NewReader(src, DialectRFC4180)
+		for {
+			if _, err := r.Next(); err != nil {
+				break
+			}
+		}
+	}
+}
+
+// #endregion Reader
+
+func assertEqual(t *testing.T, exp, got any, msgs ...any) bool {
+	t.Helper()
+	if !reflect.DeepEqual(exp, got) {
+		t.Errorf("\nExp:\n%v\nGot:\n%v\nMsg: %v", exp, got, fmt.Sprint(msgs...))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,194 @@
+package csv
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/ofabricio/scanner"
+)
+
+// #region Sniffer
+
+// ErrSniff is returned when the sample has no
+// delimiter that splits its lines consistently.
+var ErrSniff = errors.New("csv: could not determine the delimiter")
+
+// Sniffer infers the dialect of a CSV sample.
+type Sniffer struct {
+	// Lines is the number of lines sampled.
+	// Zero means 20.
+	Lines int
+	// Delimiters is the candidate delimiters in
+	// order of preference. Empty means ",;\t|".
+	Delimiters string
+	// Quotes is the candidate quotes in order
+	// of preference. Empty means "\"'".
+	Quotes string
+}
+
+// Sniff infers the dialect of a sample with the default Sniffer.
+func Sniff(sample string) (Dialect, error) {
+	var s Sniffer
+	return s.Sniff(sample)
+}
+
+// Sniff infers the delimiter, quote, header presence and
+// line terminator of a sample, usually the start of a file.
+// The delimiter is the one that splits most records into
+// the same number of fields, and more than one field.
+func (sn *Sniffer) Sniff(sample string) (Dialect, error) {
+	lines, delims, quotes := sn.Lines, sn.Delimiters, sn.Quotes
+	if lines == 0 {
+		lines = 20
+	}
+	if delims == "" {
+		delims = ",;\t|"
+	}
+	if quotes == "" {
+		quotes = "\"'"
+	}
+	// Sample whole lines, so the last record is not cut,
+	// unless the sample has fewer lines.
+	s := scanner.Scanner(sample)
+	n := 0
+	for n < lines && s.MatchUntilByte('\n') {
+		s.Next()
+		n++
+	}
+	if n == lines {
+		sample = sample[:len(sample)-len(s)]
+	}
+	d := Dialect{Quote: sniffQuote(sample, delims, quotes), LineTerminator: sniffTerminator(sample)}
+	best, bestScore := [][]string(nil), 0
+	for i := 0; i < len(delims); i++ {
+		c := Dialect{Delimiter: delims[i], Quote: d.Quote, LazyQuotes: true}
+		recs := readAll(sample, c)
+		if score := consistency(recs); score > bestScore {
+			best, bestScore, d.Delimiter = recs, score, c.Delimiter
+		}
+	}
+	if bestScore == 0 {
+		return Dialect{}, ErrSniff
+	}
+	d.Header = sniffHeader(best)
+	return d, nil
+}
+
+// sniffQuote returns the quote that most often opens a
+// field, at the start of a line or after a delimiter.
+func sniffQuote(sample, delims, quotes string) byte {
+	best, most := quotes[0], 0
+	for i := 0; i < len(quotes); i++ {
+		q, n := quotes[i], 0
+		for j := 0; j < len(sample); j++ {
+			if sample[j] != q {
+				continue
+			}
+			if j == 0 || sample[j-1] == '\n' || strings.IndexByte(delims, sample[j-1]) >= 0 {
+				n++
+			}
+		}
+		if n > most {
+			best, most = q, n
+		}
+	}
+	return best
+}
+
+// sniffTerminator returns the most common line terminator.
+func sniffTerminator(sample string) string {
+	lf := strings.Count(sample, "\n")
+	crlf := strings.Count(sample, "\r\n")
+	if crlf > 0 && crlf*2 >= lf {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+// consistency returns the number of records with the most
+// common field count, or zero if that count is one.
+func consistency(recs [][]string) int {
+	counts := map[int]int{}
+	mode := 0
+	for _, r := range recs {
+		counts[len(r)]++
+		if c := counts[len(r)]; c > counts[mode] || c == counts[mode] && len(r) > mode {
+			mode = len(r)
+		}
+	}
+	if mode < 2 {
+		return 0
+	}
+	return counts[mode]
+}
+
+// sniffHeader tells if the first record looks like a header.
+// Each column votes: if the other values are all numbers or
+// all have the same length, the first value is a header when
+// it is not a number or has another length.
+func sniffHeader(recs [][]string) bool {
+	if len(recs) < 2 {
+		return false
+	}
+	head, rest := recs[0], recs[1:]
+	votes := 0
+	for i, h := range head {
+		numeric, size := true, -1
+		for _, r := range rest {
+			if i >= len(r) {
+				continue
+			}
+			numeric = numeric && isNumber(r[i])
+			switch {
+			case size == -1:
+				size = len(r[i])
+			case size != len(r[i]):
+				size = -2
+			}
+		}
+		switch {
+		case size == -1:
+		case numeric:
+			if isNumber(h) {
+				votes--
+			} else {
+				votes++
+			}
+		case size >= 0:
+			if len(h) == size {
+				votes--
+			} else {
+				votes++
+			}
+		}
+	}
+	return votes > 0
+}
+
+// sniffNumber is the number format of the header heuristic.
+var sniffNumber = scanner.NumberFormat{Sign: true, LeadingZeros: true, Float: true, BareDot: true}
+
+func isNumber(v string) bool {
+	s := scanner.Scanner(strings.TrimSpace(v))
+	return s.UtilMatchNumberFormat(sniffNumber) && !s.More()
+}
+
+// readAll returns the records of a sample, errors aside.
+func readAll(sample string, d Dialect) [][]string {
+	r := NewReader(sample, d)
+	r.Ragged = RaggedAllow
+	var recs [][]string
+	for {
+		rec, err := r.Read()
+		if err != nil && rec == nil {
+			if err == io.EOF {
+				return recs
+			}
+			continue
+		}
+		recs = append(recs, rec)
+	}
+}
+
+// #endregion Sniffer
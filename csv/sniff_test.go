@@ -0,0 +1,71 @@
+package csv
+
+import (
+	"strings"
+	"testing"
+)
+
+// #region Sniffer
+
+func TestSniff(t *testing.T) {
+	tt := []struct {
+		give string
+		then Dialect
+		exp  error
+	}{
+		{
+			give: "name,age\nann,31\nbob,4\n",
+			then: Dialect{Delimiter: ',', Quote: '"', Header: true, LineTerminator: "\n"},
+		},
+		{
+			give: "a;1,5\r\nb;2\r\nc;3,25\r\n",
+			then: Dialect{Delimiter: ';', Quote: '"', LineTerminator: "\r\n"},
+		},
+		{
+			give: "id\tcode\n1\tAB\n2\tCD",
+			then: Dialect{Delimiter: '\t', Quote: '"', Header: true, LineTerminator: "\n"},
+		},
+		{
+			give: "'a|b'|c\n'd'|e\n",
+			then: Dialect{Delimiter: '|', Quote: '\'', LineTerminator: "\n"},
+		},
+		{
+			give: "\"x, y\",z\n\"w, v\",u\n",
+			then: Dialect{Delimiter: ',', Quote: '"', LineTerminator: "\n"},
+		},
+		{
+			give: "just\none\ncolumn\n",
+			exp:  ErrSniff,
+		},
+	}
+	for _, tc := range tt {
+		got, err := Sniff(tc.give)
+		assertEqual(t, tc.exp, err, tc.give)
+		assertEqual(t, tc.then, got, tc.give)
+	}
+}
+
+func TestSnifferLines(t *testing.T) {
+	// The lines past the sample don't count.
+	src := "a,b\nc,d\n" + strings.Repeat("e;f;g\n", 10)
+	sn := Sniffer{Lines: 2}
+	d, err := sn.Sniff(src)
+	assertEqual(t, nil, err)
+	assertEqual(t, byte(','), d.Delimiter)
+
+	// The sniffed dialect feeds the reader.
+	recs, err := NewReader("a;b\nc;d", Dialect{Delimiter: ';'}).ReadAll()
+	assertEqual(t, nil, err)
+	d, _ = Sniff("a;b\nc;d")
+	got, _ := NewReader("a;b\nc;d", d).ReadAll()
+	assertEqual(t, recs, got)
+}
+
+func BenchmarkSniff(b *testing.B) {
+	src := "name,age,city\n" + strings.Repeat("ann,31,\"Rio, RJ\"\n", 30)
+	for i := 0; i < b.N; i++ {
+		Sniff(src)
+	}
+}
+
+// #endregion Sniffer
@@ -0,0 +1,184 @@
+package scanner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// #region Diagnostics
+
+// UtilRecover skips to the next byte of sync, like
+// MatchUntilAnyByte, so a parser can carry on after a
+// syntax error. With b set, the brackets, strings and
+// comments of b are skipped whole, so a sync byte
+// inside them is not taken. Returns false at EOF.
+func (s *Scanner) UtilRecover(sync string, b *Balanced) bool {
+	ss := *s
+	for len(ss) > 0 {
+		if strings.IndexByte(sync, ss[0]) >= 0 {
+			*s = ss
+			return true
+		}
+		if b != nil {
+			if q, ok := b.quote(ss); ok {
+				if n, ok := ss.matchQuote(q); ok {
+					ss = ss[n:]
+					continue
+				}
+			}
+			if t := b.Trivia; t != nil {
+				if t.line(ss) {
+					ss = ss[ss.matchLine():]
+					continue
+				}
+				if p, ok := t.block(ss); ok {
+					if n, ok := ss.matchBlock(p, t.Nested); ok {
+						ss = ss[n:]
+						continue
+					}
+				}
+			}
+			if ok, _ := ss.UtilMatchBalanced(b); ok {
+				continue
+			}
+		}
+		ss = ss[1:]
+	}
+	*s = ss
+	return false
+}
+
+// Diagnostic is a positioned error message.
+type Diagnostic struct {
+	Span Span
+	Line int // 1-based line of Span.Start.
+	Col  int // 1-based column of Span.Start.
+	Msg  string
+}
+
+func (d Diagnostic) Error() string {
+	return strconv.Itoa(d.Line) + ":" + strconv.Itoa(d.Col) + ": " + d.Msg
+}
+
+// ParseError is a positioned error that stops a parse,
+// for parsers that report a single error.
+type ParseError struct {
+	Line int // 1-based line of the error.
+	Col  int // 1-based column of the error, in bytes.
+	Err  error
+	// Pkg prefixes the message, like "toml".
+	Pkg string
+	// Key is the name the error is about, like
+	// a duplicate key. It is quoted after Err.
+	Key string
+}
+
+func (e *ParseError) Error() string {
+	msg := e.Err.Error()
+	if e.Pkg != "" {
+		msg = e.Pkg + ": " + msg
+	}
+	if e.Key != "" {
+		msg += " " + strconv.Quote(e.Key)
+	}
+	return msg + " at " + strconv.Itoa(e.Line) + ":" + strconv.Itoa(e.Col)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Diagnostics collects the errors of a parse, so a
+// single parse reports all of them. Only the first
+// error of a position is kept, since the others are
+// usually a cascade of it.
+type Diagnostics struct {
+	// Src is the start of the input, for positions.
+	Src Scanner
+	// Max is the maximum number of diagnostics.
+	// Zero means no limit.
+	Max int
+	// List is the diagnostics in the order added.
+	List []Diagnostic
+
+	dropped int
+}
+
+// Add adds a diagnostic for a span.
+// Returns false if Max was reached.
+func (d *Diagnostics) Add(sp Span, msg string) bool {
+	for _, v := range d.List {
+		if v.Span.Start == sp.Start {
+			return !d.Full()
+		}
+	}
+	if d.Full() {
+		d.dropped++
+		return false
+	}
+	line, col := d.Src.LineCol(sp.Start)
+	d.List = append(d.List, Diagnostic{Span: sp, Line: line, Col: col, Msg: msg})
+	return true
+}
+
+// Errorf adds a diagnostic at a mark of Src.
+// Returns false if Max was reached.
+func (d *Diagnostics) Errorf(at Scanner, format string, args ...any) bool {
+	off := len(d.Src) - len(at)
+	return d.Add(Span{off, off}, fmt.Sprintf(format, args...))
+}
+
+// Expect matches v and tells if it matched. A missing v
+// is recorded as a diagnostic and the scanner is left as
+// is, so the parser can carry on as if v was there.
+func (d *Diagnostics) Expect(s *Scanner, v string) bool {
+	if s.Match(v) {
+		return true
+	}
+	d.Errorf(*s, "expected %q", v)
+	return false
+}
+
+// Missing reports a missing token and returns it as
+// a zero-width token of kind k at a mark of Src, so
+// a parser can insert it in place of the missing one.
+func (d *Diagnostics) Missing(at Scanner, k Kind, what string) Token {
+	off := len(d.Src) - len(at)
+	d.Add(Span{off, off}, "missing "+what)
+	return Token{Kind: k, Span: Span{off, off}}
+}
+
+// Full tells if Max was reached.
+func (d *Diagnostics) Full() bool {
+	return d.Max > 0 && len(d.List) >= d.Max
+}
+
+// Len returns the number of diagnostics.
+func (d *Diagnostics) Len() int {
+	return len(d.List)
+}
+
+// Err returns d as an error, or nil if it is empty.
+func (d *Diagnostics) Err() error {
+	if len(d.List) == 0 {
+		return nil
+	}
+	return d
+}
+
+func (d *Diagnostics) Error() string {
+	var b strings.Builder
+	for i, v := range d.List {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(v.Error())
+	}
+	if d.dropped > 0 {
+		b.WriteString("\n(and " + strconv.Itoa(d.dropped) + " more errors)")
+	}
+	return b.String()
+}
+
+// #endregion Diagnostics
@@ -0,0 +1,162 @@
+package scanner
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+// #region Diagnostics
+
+func TestScannerUtilRecover(t *testing.T) {
+	tt := []struct {
+		give string
+		sync string
+		when *Balanced
+		then bool
+		exp  string
+	}{
+		{give: `a b; c`, sync: ";\n", then: true, exp: `; c`},
+		{give: "a b\n c", sync: ";\n", then: true, exp: "\n c"},
+		{give: `a (b; c); d`, sync: ";", then: true, exp: `; c); d`},
+		{give: `a (b; c); d`, sync: ";", when: &BalancedC, then: true, exp: `; d`},
+		{give: `a ";" /* ; */ // ;` + "\n; d", sync: ";", when: &BalancedC, then: true, exp: `; d`},
+		{give: `a ( ; }`, sync: ";}", when: &BalancedC, then: true, exp: `; }`},
+		{give: `a b`, sync: ";", then: false, exp: ``},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		assertEqual(t, tc.then, s.UtilRecover(tc.sync, tc.when), tc.give)
+		assertEqual(t, tc.exp, s.String(), tc.give)
+	}
+}
+
+func BenchmarkScannerUtilRecover(b *testing.B) {
+	x := Scanner(`a (b; c) "d;"; e`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilRecover(";", &BalancedC)
+	}
+}
+
+// parseAssigns parses lines like "key = 1;" for the tests.
+func parseAssigns(src string, max int) (map[string]string, *Diagnostics) {
+	out := map[string]string{}
+	s := Scanner(src)
+	d := &Diagnostics{Src: s, Max: max}
+	for s.WS() && s.More() && !d.Full() {
+		k := s.TokenFor(func() bool { return s.UtilMatchIdent(IdentGo) })
+		if k == "" {
+			d.Errorf(s, "expected key")
+			s.UtilRecover(";", nil)
+			s.Next()
+			continue
+		}
+		s.WS()
+		d.Expect(&s, "=")
+		s.WS()
+		v := s.TokenFor(s.UtilMatchInteger)
+		if v == "" {
+			d.Missing(s, KindError, "value")
+		}
+		s.WS()
+		if !s.MatchByte(';') {
+			d.Errorf(s, "expected ';'")
+			s.UtilRecover(";\n", nil)
+			s.Next()
+		}
+		out[k] = v
+	}
+	return out, d
+}
+
+func TestDiagnostics(t *testing.T) {
+	tt := []struct {
+		give string
+		max  int
+		then map[string]string
+		err  string
+	}{
+		{
+			give: "a = 1;\nb = 2;",
+			then: map[string]string{"a": "1", "b": "2"},
+		},
+		{
+			give: "a 1;\nb = ;\n? = 3;\nc = 4",
+			then: map[string]string{"a": "1", "b": "", "c": "4"},
+			err:  "1:3: expected \"=\"\n2:5: missing value\n3:1: expected key\n4:6: expected ';'",
+		},
+		{
+			give: "a = x y z;",
+			then: map[string]string{"a": ""},
+			err:  "1:5: missing value",
+		},
+		{
+			give: "? ;\n? ;\n? ;\n? ;",
+			max:  2,
+			then: map[string]string{},
+			err:  "1:1: expected key\n2:1: expected key",
+		},
+	}
+	for _, tc := range tt {
+		got, d := parseAssigns(tc.give, tc.max)
+		assertEqual(t, tc.then, got, tc.give)
+		if tc.err == "" {
+			assertEqual(t, nil, d.Err(), tc.give)
+			continue
+		}
+		assertEqual(t, tc.err, d.Err().Error(), tc.give)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tt := []struct {
+		give *ParseError
+		exp  string
+	}{
+		{give: &ParseError{Line: 2, Col: 3, Err: io.ErrUnexpectedEOF}, exp: "unexpected EOF at 2:3"},
+		{give: &ParseError{Line: 1, Col: 1, Err: io.ErrUnexpectedEOF, Pkg: "x"}, exp: "x: unexpected EOF at 1:1"},
+		{give: &ParseError{Line: 1, Col: 5, Err: io.ErrUnexpectedEOF, Pkg: "x", Key: "a b"}, exp: "x: unexpected EOF \"a b\" at 1:5"},
+	}
+	for _, tc := range tt {
+		assertEqual(t, tc.exp, tc.give.Error())
+		assertEqual(t, true, errors.Is(tc.give, io.ErrUnexpectedEOF))
+	}
+}
+
+func TestDiagnosticsAdd(t *testing.T) {
+	d := Diagnostics{Src: Scanner("ab\ncd"), Max: 2}
+	assertEqual(t, true, d.Add(Span{3, 4}, "one"))
+	assertEqual(t, true, d.Add(Span{3, 5}, "cascade"))
+	assertEqual(t, 1, d.Len())
+	assertEqual(t, true, d.Add(Span{0, 1}, "two"))
+	assertEqual(t, true, d.Full())
+	assertEqual(t, false, d.Add(Span{1, 2}, "three"))
+	assertEqual(t, false, d.Add(Span{2, 2}, "four"))
+	assertEqual(t, Diagnostic{Span: Span{3, 4}, Line: 2, Col: 1, Msg: "one"}, d.List[0])
+	assertEqual(t, "2:1: one\n1:1: two\n(and 2 more errors)", d.Error())
+	var e *Diagnostics
+	assertEqual(t, true, errors.As(d.Err(), &e))
+}
+
+func TestDiagnosticsExpect(t *testing.T) {
+	s := Scanner("= b")
+	d := Diagnostics{Src: s}
+	assertEqual(t, true, d.Expect(&s, "="))
+	assertEqual(t, 0, d.Len())
+	s.WS()
+	assertEqual(t, false, d.Expect(&s, "="))
+	assertEqual(t, "b", s.String())
+	assertEqual(t, `1:3: expected "="`, d.Error())
+}
+
+func TestDiagnosticsMissing(t *testing.T) {
+	s := Scanner("a b")
+	d := Diagnostics{Src: s}
+	s.Advance(2)
+	tok := d.Missing(s, KindError, "'='")
+	assertEqual(t, Token{Kind: KindError, Span: Span{2, 2}}, tok)
+	assertEqual(t, "1:3: missing '='", d.Error())
+}
+
+// #endregion Diagnostics
@@ -0,0 +1,403 @@
+// Package dotenv parses .env files with the scanner
+// and expands their variable references.
+package dotenv
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/ofabricio/scanner"
+)
+
+// #region Errors
+
+var (
+	ErrName     = errors.New("invalid variable name")
+	ErrAssign   = errors.New("missing '='")
+	ErrQuote    = errors.New("unclosed quote")
+	ErrTrailing = errors.New("unexpected text after value")
+	ErrBrace    = errors.New("unclosed '${'")
+	ErrCycle    = errors.New("reference cycle")
+)
+
+// ParseError is a positioned error.
+type ParseError struct {
+	scanner.ParseError
+	// Cycle is the names of a reference
+	// cycle, like [A B A], for ErrCycle.
+	Cycle []string
+}
+
+func (e *ParseError) Error() string {
+	if len(e.Cycle) == 0 {
+		return e.ParseError.Error()
+	}
+	return "dotenv: " + e.Err.Error() + " " + strings.Join(e.Cycle, " -> ") +
+		" at " + strconv.Itoa(e.Line) + ":" + strconv.Itoa(e.Col)
+}
+
+// #endregion Errors
+
+// #region Parse
+
+// Var is a variable definition.
+type Var struct {
+	Name string
+	// Raw is the value as in the input, without quotes.
+	Raw string
+	// Quote is the quote of the value, '\'', '"' or zero.
+	// Single quoted values are not expanded.
+	Quote byte
+	Line  int // 1-based line of the name.
+	Col   int // 1-based column of the name.
+
+	raw int // Offset of Raw.
+}
+
+// name is a variable name.
+var name = scanner.NewIdent(isNameStart, isNameContinue)
+
+func isNameStart(r rune) bool {
+	return r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+func isNameContinue(r rune) bool {
+	return isNameStart(r) || r >= '0' && r <= '9'
+}
+
+// Parse parses the definitions of a .env file, in order.
+// Lines are blank, comments starting with '#', or like:
+//
+//	export NAME=value # comment
+//	NAME='verbatim'
+//	NAME="escapes \n and
+//	more lines"
+//
+// Unquoted values end at a '#' after a whitespace and
+// lose the whitespace around them.
+func Parse(src string) ([]Var, error) {
+	p := parser{src: scanner.Scanner(src), s: scanner.Scanner(src)}
+	var vars []Var
+	for p.s.More() {
+		p.ws()
+		if p.s.MatchByte('\n') || p.s.Match("\r\n") {
+			continue
+		}
+		if p.s.EqualByte('#') {
+			p.s.MatchUntilByte('\n')
+			if !p.s.MatchByte('\n') {
+				p.s.Advance(len(p.s))
+			}
+			continue
+		}
+		if m := p.s; p.s.Match("export") && !p.ws() {
+			p.s = m
+		}
+		v := Var{}
+		v.Line, v.Col = p.src.LineCol(p.off(p.s))
+		m := p.s
+		if !p.s.UtilMatchIdent(name) {
+			return nil, p.errorf(p.s, ErrName)
+		}
+		v.Name = p.s.Token(m)
+		p.ws()
+		if !p.s.MatchByte('=') {
+			return nil, p.errorf(p.s, ErrAssign)
+		}
+		p.ws()
+		if err := p.value(&v); err != nil {
+			return nil, err
+		}
+		vars = append(vars, v)
+	}
+	return vars, nil
+}
+
+type parser struct {
+	src, s scanner.Scanner
+}
+
+// value scans a value and the rest of its line.
+func (p *parser) value(v *Var) error {
+	if q := p.s.Curr(); p.s.More() && (q == '\'' || q == '"') {
+		ini := p.s
+		p.s.Next()
+		m := p.s
+		if q == '"' && !p.quoted() || q == '\'' && !p.s.MatchUntilByte(q) {
+			return p.errorf(ini, ErrQuote)
+		}
+		v.Raw, v.Quote, v.raw = p.s.Token(m), q, p.off(m)
+		p.s.Next()
+		p.ws()
+		if p.s.More() && !p.s.EqualByte('#') && !p.s.EqualByte('\n') && !p.s.Equal("\r\n") {
+			return p.errorf(p.s, ErrTrailing)
+		}
+		p.skipLine()
+		return nil
+	}
+	m := p.s
+	for p.s.More() && !p.s.EqualByte('\n') && !p.s.Equal("\r\n") {
+		if p.s.EqualByte('#') && len(m) > len(p.s) && isSpace(m[len(m)-len(p.s)-1]) {
+			break
+		}
+		p.s.Next()
+	}
+	v.Raw, v.raw = strings.TrimRight(p.s.Token(m), " \t"), p.off(m)
+	p.skipLine()
+	return nil
+}
+
+// quoted scans a double-quoted value up to
+// the closing quote, skipping escaped bytes.
+// It tells if the quote was found.
+func (p *parser) quoted() bool {
+	for p.s.More() && !p.s.EqualByte('"') {
+		if p.s.EqualByte('\\') {
+			p.s.Next()
+		}
+		p.s.Next()
+	}
+	return p.s.More()
+}
+
+// skipLine skips past the next line break.
+func (p *parser) skipLine() {
+	if p.s.MatchUntilByte('\n') {
+		p.s.Next()
+		return
+	}
+	p.s.Advance(len(p.s))
+}
+
+// ws skips spaces and tabs.
+func (p *parser) ws() bool {
+	return p.s.MatchWhileByteBy(isSpace)
+}
+
+func (p *parser) off(at scanner.Scanner) int {
+	return len(p.src) - len(at)
+}
+
+func (p *parser) errorf(at scanner.Scanner, err error) error {
+	line, col := p.src.LineCol(p.off(at))
+	return &ParseError{ParseError: scanner.ParseError{Line: line, Col: col, Err: err, Pkg: "dotenv"}}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+// #endregion Parse
+
+// #region Expand
+
+// Load parses src and expands its variables.
+func Load(src string, lookup func(string) (string, bool)) (map[string]string, error) {
+	vars, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	return Expand(src, vars, lookup)
+}
+
+// Expand returns the values of the variables of src with
+// their references expanded. References are $NAME, ${NAME},
+// ${NAME:-default}, for unset or empty, and ${NAME-default},
+// for unset. '\$' is a literal '$'. A reference is to the
+// last definition of a name in the file, in any order, or to
+// a lookup, usually os.LookupEnv, if there is none. In
+// NAME=${NAME}x the reference is to the definition before,
+// or to the lookup. A cycle of references is an error.
+// Double quoted values also take the escapes \n, \r, \t,
+// \" and \\.
+func Expand(src string, vars []Var, lookup func(string) (string, bool)) (map[string]string, error) {
+	e := expander{
+		src:    scanner.Scanner(src),
+		vars:   vars,
+		lookup: lookup,
+		state:  make([]int8, len(vars)),
+		values: make([]string, len(vars)),
+	}
+	out := make(map[string]string, len(vars))
+	for i := range vars {
+		v, err := e.resolve(i)
+		if err != nil {
+			return nil, err
+		}
+		out[vars[i].Name] = v
+	}
+	return out, nil
+}
+
+type expander struct {
+	src    scanner.Scanner
+	vars   []Var
+	lookup func(string) (string, bool)
+	state  []int8 // 0 unvisited, 1 visiting, 2 done.
+	values []string
+	stack  []int // Vars being expanded.
+	skip   int   // Depth of defaults not taken.
+}
+
+// resolve returns the expanded value of a var.
+func (e *expander) resolve(i int) (string, error) {
+	switch e.state[i] {
+	case 2:
+		return e.values[i], nil
+	case 1:
+		var cycle []string
+		for k := len(e.stack) - 1; k >= 0; k-- {
+			cycle = append([]string{e.vars[e.stack[k]].Name}, cycle...)
+			if e.stack[k] == i {
+				break
+			}
+		}
+		cycle = append(cycle, e.vars[i].Name)
+		v := e.vars[e.stack[len(e.stack)-1]]
+		return "", &ParseError{ParseError: scanner.ParseError{Line: v.Line, Col: v.Col, Err: ErrCycle, Pkg: "dotenv"}, Cycle: cycle}
+	}
+	v := e.vars[i]
+	if v.Quote == '\'' {
+		e.state[i], e.values[i] = 2, v.Raw
+		return v.Raw, nil
+	}
+	e.state[i] = 1
+	e.stack = append(e.stack, i)
+	s := scanner.Scanner(v.Raw)
+	val, err := e.text(&s, i, false)
+	if err != nil {
+		return "", err
+	}
+	e.stack = e.stack[:len(e.stack)-1]
+	e.state[i], e.values[i] = 2, val
+	return val, nil
+}
+
+// text expands the text of var i up to the end,
+// or up to a '}' when in a default.
+func (e *expander) text(s *scanner.Scanner, i int, inner bool) (string, error) {
+	var b strings.Builder
+	dq := e.vars[i].Quote == '"'
+	for s.More() {
+		c := s.Curr()
+		switch {
+		case inner && c == '}':
+			return b.String(), nil
+		case c == '\\' && len(*s) > 1:
+			n := (*s)[1]
+			switch {
+			case n == '$':
+				b.WriteByte('$')
+			case dq && n == 'n':
+				b.WriteByte('\n')
+			case dq && n == 'r':
+				b.WriteByte('\r')
+			case dq && n == 't':
+				b.WriteByte('\t')
+			case dq && (n == '"' || n == '\\'):
+				b.WriteByte(n)
+			default:
+				b.WriteByte(c)
+				s.Next()
+				continue
+			}
+			s.Advance(2)
+		case c == '$':
+			v, err := e.ref(s, i)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+		default:
+			b.WriteByte(c)
+			s.Next()
+		}
+	}
+	if inner {
+		return "", e.errorf(*s, i, ErrBrace)
+	}
+	return b.String(), nil
+}
+
+// ref expands a reference at s, in the text of var i.
+func (e *expander) ref(s *scanner.Scanner, i int) (string, error) {
+	ini := *s
+	s.Next()
+	braced := s.MatchByte('{')
+	m := *s
+	if !s.UtilMatchIdent(name) {
+		if braced {
+			return "", e.errorf(ini, i, ErrBrace)
+		}
+		return "$", nil
+	}
+	key := s.Token(m)
+	var val string
+	var set bool
+	var err error
+	if e.skip == 0 {
+		val, set, err = e.get(key, i)
+	}
+	if err != nil || !braced {
+		return val, err
+	}
+	switch {
+	case s.MatchByte('}'):
+		return val, nil
+	case s.Match(":-"):
+		return e.fallback(s, i, val, set && val != "")
+	case s.MatchByte('-'):
+		return e.fallback(s, i, val, set)
+	}
+	return "", e.errorf(ini, i, ErrBrace)
+}
+
+// fallback expands the default of a reference up to its
+// '}', and returns val instead if keep is true. A default
+// that is not taken is only scanned, so its references are
+// not looked up.
+func (e *expander) fallback(s *scanner.Scanner, i int, val string, keep bool) (string, error) {
+	if keep {
+		e.skip++
+	}
+	def, err := e.text(s, i, true)
+	if keep {
+		e.skip--
+	}
+	if err != nil {
+		return "", err
+	}
+	s.Next()
+	if keep {
+		return val, nil
+	}
+	return def, nil
+}
+
+// get returns the value of a name referenced by var i.
+func (e *expander) get(key string, i int) (string, bool, error) {
+	self := e.vars[i].Name == key
+	for k := len(e.vars) - 1; k >= 0; k-- {
+		if e.vars[k].Name != key || self && k >= i {
+			continue
+		}
+		v, err := e.resolve(k)
+		return v, true, err
+	}
+	if e.lookup != nil {
+		v, ok := e.lookup(key)
+		return v, ok, nil
+	}
+	return "", false, nil
+}
+
+// errorf returns an error at a mark in the value of var i.
+func (e *expander) errorf(at scanner.Scanner, i int, err error) error {
+	v := e.vars[i]
+	off := v.raw + len(v.Raw) - len(at)
+	line, col := e.src.LineCol(off)
+	return &ParseError{ParseError: scanner.ParseError{Line: line, Col: col, Err: err, Pkg: "dotenv"}}
+}
+
+// #endregion Expand
@@ -0,0 +1,143 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// #region Parse
+
+func TestParse(t *testing.T) {
+	src := "# comment\n" +
+		"A=1\n" +
+		"export B = two words  # note\n" +
+		"  C='single # $A'\n" +
+		"D=\"multi\nline \\\" q\" # c\n" +
+		"\n" +
+		"E=x#y\n" +
+		"F=\r\n" +
+		"exported=1\n"
+	vars, err := Parse(src)
+	assertEqual(t, nil, err)
+	assertEqual(t, []Var{
+		{Name: "A", Raw: "1", Line: 2, Col: 1, raw: 12},
+		{Name: "B", Raw: "two words", Line: 3, Col: 8, raw: 25},
+		{Name: "C", Raw: "single # $A", Quote: '\'', Line: 4, Col: 3, raw: 48},
+		{Name: "D", Raw: "multi\nline \\\" q", Quote: '"', Line: 5, Col: 1, raw: 64},
+		{Name: "E", Raw: "x#y", Line: 8, Col: 1, raw: 88},
+		{Name: "F", Raw: "", Line: 9, Col: 1, raw: 94},
+		{Name: "exported", Raw: "1", Line: 10, Col: 1, raw: 105},
+	}, vars)
+}
+
+func TestParseError(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+		err  error
+	}{
+		{give: "A=1\n1A=2", exp: "dotenv: invalid variable name at 2:1", err: ErrName},
+		{give: "A 1", exp: "dotenv: missing '=' at 1:3", err: ErrAssign},
+		{give: "A=1\nB=\"open\n", exp: "dotenv: unclosed quote at 2:3", err: ErrQuote},
+		{give: "A=\"abc\\", exp: "dotenv: unclosed quote at 1:3", err: ErrQuote},
+		{give: "A=\"abc\\\"", exp: "dotenv: unclosed quote at 1:3", err: ErrQuote},
+		{give: "A='x'y", exp: "dotenv: unexpected text after value at 1:6", err: ErrTrailing},
+	}
+	for _, tc := range tt {
+		_, err := Parse(tc.give)
+		if assertEqual(t, true, err != nil, tc.give) {
+			assertEqual(t, tc.exp, err.Error(), tc.give)
+			assertEqual(t, true, errors.Is(err, tc.err), tc.give)
+		}
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	src := "# c\nexport HOST=localhost\nPORT=8080 # p\nURL=\"http://${HOST}:${PORT}\"\nKEY='x$y'\n"
+	for i := 0; i < b.N; i++ {
+		Parse(src)
+	}
+}
+
+// #endregion Parse
+
+// #region Expand
+
+func TestLoad(t *testing.T) {
+	env := map[string]string{"HOME": "/home/u", "EMPTY": "", "PATH": "/bin"}
+	lookup := func(k string) (string, bool) {
+		v, ok := env[k]
+		return v, ok
+	}
+	tt := []struct {
+		give string
+		then map[string]string
+	}{
+		{give: "A=$HOME/x", then: map[string]string{"A": "/home/u/x"}},
+		{give: "A=${HOME}x", then: map[string]string{"A": "/home/ux"}},
+		{give: "A=${NOPE:-d}\nB=${EMPTY:-d}\nC=${EMPTY-d}\nD=${HOME:-d}", then: map[string]string{"A": "d", "B": "d", "C": "", "D": "/home/u"}},
+		{give: "A=${NOPE:-${HOME}/d}", then: map[string]string{"A": "/home/u/d"}},
+		{give: "A=${B}\nB=b", then: map[string]string{"A": "b", "B": "b"}},
+		{give: "PATH=${PATH}:/x\nPATH=${PATH}:/y", then: map[string]string{"PATH": "/bin:/x:/y"}},
+		{give: "A='$HOME'", then: map[string]string{"A": "$HOME"}},
+		{give: `A="\$HOME\t\\"`, then: map[string]string{"A": "$HOME\t\\"}},
+		{give: `A=\$x\n $ $1`, then: map[string]string{"A": `$x\n $ $1`}},
+		{give: "A=$HOME.txt", then: map[string]string{"A": "/home/u.txt"}},
+		{give: "A=${C:-$B}\nB=$A\nC=c", then: map[string]string{"A": "c", "B": "c", "C": "c"}},
+		{give: "A=${HOME-${B:-${C}}}\nB=$A", then: map[string]string{"A": "/home/u", "B": "/home/u"}},
+	}
+	for _, tc := range tt {
+		got, err := Load(tc.give, lookup)
+		assertEqual(t, nil, err, tc.give)
+		assertEqual(t, tc.then, got, tc.give)
+	}
+}
+
+func TestLoadError(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+		err  error
+	}{
+		{give: "A=${B}\nB=${C}\nC=$A", exp: "dotenv: reference cycle A -> B -> C -> A at 3:1", err: ErrCycle},
+		{give: "X=1\nA=${A:-${A}}", exp: "", err: nil},
+		{give: "A=1\nB=\"x ${A\"", exp: "dotenv: unclosed '${' at 2:6", err: ErrBrace},
+		{give: "A=${A:-x", exp: "dotenv: unclosed '${' at 1:9", err: ErrBrace},
+		{give: "A=${}", exp: "dotenv: unclosed '${' at 1:3", err: ErrBrace},
+	}
+	for _, tc := range tt {
+		_, err := Load(tc.give, nil)
+		if tc.err == nil {
+			assertEqual(t, nil, err, tc.give)
+			continue
+		}
+		if assertEqual(t, true, err != nil, tc.give) {
+			assertEqual(t, tc.exp, err.Error(), tc.give)
+			assertEqual(t, true, errors.Is(err, tc.err), tc.give)
+		}
+	}
+	_, err := Load("A=$B\nB=$A", nil)
+	var pe *ParseError
+	assertEqual(t, true, errors.As(err, &pe))
+	assertEqual(t, []string{"A", "B", "A"}, pe.Cycle)
+}
+
+func BenchmarkLoad(b *testing.B) {
+	src := "HOST=localhost\nPORT=8080\nURL=\"http://${HOST}:${PORT:-80}/$PATH\"\n"
+	for i := 0; i < b.N; i++ {
+		Load(src, nil)
+	}
+}
+
+// #endregion Expand
+
+func assertEqual(t *testing.T, exp, got any, msgs ...any) bool {
+	t.Helper()
+	if !reflect.DeepEqual(exp, got) {
+		t.Errorf("\nExp:\n%v\nGot:\n%v\nMsg: %v", exp, got, fmt.Sprint(msgs...))
+		return false
+	}
+	return true
+}
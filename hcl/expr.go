@@ -0,0 +1,641 @@
+package hcl
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ofabricio/scanner"
+)
+
+// #region Expr
+
+// Expr is an expression node.
+type Expr interface {
+	// Span returns the source range of the expression.
+	Span() scanner.Span
+	expr()
+}
+
+// base holds the span of an expression.
+type base struct {
+	span scanner.Span
+}
+
+func (b base) Span() scanner.Span {
+	return b.span
+}
+
+func (base) expr() {}
+
+// Literal is a number, bool, null or string fragment.
+// Value is int64, float64, bool, nil or string.
+type Literal struct {
+	base
+	Value any
+}
+
+// Template is a quoted string or a heredoc.
+// Parts are string Literals and interpolations.
+type Template struct {
+	base
+	Parts   []Expr
+	Heredoc bool
+}
+
+// Literal returns the text of a template
+// without interpolations.
+func (t *Template) Literal() (string, bool) {
+	var b strings.Builder
+	for _, v := range t.Parts {
+		l, ok := v.(*Literal)
+		if !ok {
+			return "", false
+		}
+		b.WriteString(l.Value.(string))
+	}
+	return b.String(), true
+}
+
+// Variable is a reference to a name, like var.
+type Variable struct {
+	base
+	Name string
+}
+
+// GetAttr is an attribute access, like X.Name.
+type GetAttr struct {
+	base
+	X    Expr
+	Name string
+}
+
+// Index is an index access, like X[Key] or X.0.
+type Index struct {
+	base
+	X, Key Expr
+}
+
+// Call is a function call, like Name(Args).
+// Expand tells if the last argument
+// is expanded, like f(list...).
+type Call struct {
+	base
+	Name   string
+	Args   []Expr
+	Expand bool
+}
+
+// Unary is a unary operation, '-' or '!'.
+type Unary struct {
+	base
+	Op string
+	X  Expr
+}
+
+// Binary is a binary operation, like X + Y.
+type Binary struct {
+	base
+	Op   string
+	X, Y Expr
+}
+
+// Conditional is a Cond ? True : False expression.
+type Conditional struct {
+	base
+	Cond, True, False Expr
+}
+
+// Paren is a parenthesized expression.
+type Paren struct {
+	base
+	X Expr
+}
+
+// Tuple is a list, like [1, 2].
+type Tuple struct {
+	base
+	Items []Expr
+}
+
+// Object is a map, like { a = 1, "b" : 2 }.
+// Identifier keys are string Literals.
+type Object struct {
+	base
+	Items []ObjectItem
+}
+
+// ObjectItem is a key and value of an Object.
+type ObjectItem struct {
+	Key, Value Expr
+}
+
+// ParseExpr parses a single expression.
+// Line breaks are whitespaces in it.
+func ParseExpr(src string) (Expr, error) {
+	p := parser{src: scanner.Scanner(src), s: scanner.Scanner(src), nest: 1}
+	p.ws(true)
+	x, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	p.ws(true)
+	if p.s.More() {
+		return nil, p.unexpected(ErrTrailing)
+	}
+	return x, nil
+}
+
+// ops are the binary operators and prec their precedences.
+var (
+	opNames = []string{"||", "&&", "==", "!=", "<", ">", "<=", ">=", "+", "-", "*", "/", "%"}
+	ops     = scanner.NewLiterals(opNames...)
+	prec    = []int{1, 2, 3, 3, 4, 4, 4, 4, 5, 5, 6, 6, 6}
+)
+
+// expr parses an expression.
+func (p *parser) expr() (Expr, error) {
+	c, err := p.binary(1)
+	if err != nil {
+		return nil, err
+	}
+	m := p.s
+	p.ws(p.nest > 0)
+	if !p.s.MatchByte('?') {
+		p.s = m
+		return c, nil
+	}
+	p.ws(p.nest > 0)
+	t, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	p.ws(p.nest > 0)
+	if !p.s.MatchByte(':') {
+		return nil, p.unexpected(ErrColon)
+	}
+	p.ws(p.nest > 0)
+	f, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	return &Conditional{base{c.Span().Merge(f.Span())}, c, t, f}, nil
+}
+
+// binary parses binary operations of
+// precedence min or higher.
+func (p *parser) binary(min int) (Expr, error) {
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		m := p.s
+		p.ws(p.nest > 0)
+		if p.s.Equal("/*") {
+			// An unclosed comment, not a division.
+			p.s = m
+			return x, nil
+		}
+		id, ok := p.s.MatchLongest(ops)
+		if !ok || prec[id] < min {
+			p.s = m
+			return x, nil
+		}
+		p.ws(p.nest > 0)
+		y, err := p.binary(prec[id] + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = &Binary{base{x.Span().Merge(y.Span())}, opNames[id], x, y}
+	}
+}
+
+// unary parses a unary operation or an operand.
+func (p *parser) unary() (Expr, error) {
+	at := p.s
+	if c := p.s.Curr(); c == '-' || c == '!' {
+		p.s.Next()
+		p.ws(p.nest > 0)
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &Unary{base{p.src.Span(at, p.s)}, string(c), x}, nil
+	}
+	return p.postfix()
+}
+
+// postfix parses an operand and its attribute and index accesses.
+func (p *parser) postfix() (Expr, error) {
+	at := p.s
+	x, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case !p.s.Equal("...") && p.s.MatchByte('.'):
+			m := p.s
+			if n := p.s.TokenByteBy(isDigit); n != "" {
+				i, err := strconv.ParseInt(n, 10, 64)
+				if err != nil {
+					return nil, p.errorf(m, ErrNumber)
+				}
+				key := &Literal{base{p.src.Span(m, p.s)}, i}
+				x = &Index{base{p.src.Span(at, p.s)}, x, key}
+				continue
+			}
+			name := p.ident()
+			if name == "" {
+				return nil, p.unexpected(ErrIdent)
+			}
+			x = &GetAttr{base{p.src.Span(at, p.s)}, x, name}
+		case p.s.MatchByte('['):
+			var key Expr
+			err := p.nested(func() (err error) {
+				key, err = p.expr()
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+			if !p.s.MatchByte(']') {
+				return nil, p.unexpected(ErrBracket)
+			}
+			x = &Index{base{p.src.Span(at, p.s)}, x, key}
+		default:
+			return x, nil
+		}
+	}
+}
+
+// primary parses an operand.
+func (p *parser) primary() (Expr, error) {
+	at := p.s
+	switch c := p.s.Curr(); {
+	case !p.s.More():
+		return nil, p.errorf(p.s, ErrExpr)
+	case isDigit(c):
+		return p.number()
+	case c == '"':
+		return p.template()
+	case p.s.Equal("<<"):
+		return p.heredoc()
+	case c == '(':
+		p.s.Next()
+		var x Expr
+		err := p.nested(func() (err error) {
+			x, err = p.expr()
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !p.s.MatchByte(')') {
+			return nil, p.unexpected(ErrParen)
+		}
+		return &Paren{base{p.src.Span(at, p.s)}, x}, nil
+	case c == '[':
+		return p.tuple()
+	case c == '{':
+		return p.object()
+	}
+	name := p.ident()
+	sp := p.src.Span(at, p.s)
+	switch name {
+	case "":
+		return nil, p.unexpected(ErrExpr)
+	case "true", "false":
+		return &Literal{base{sp}, name == "true"}, nil
+	case "null":
+		return &Literal{base{sp}, nil}, nil
+	}
+	if p.s.EqualByte('(') {
+		return p.call(name, at)
+	}
+	return &Variable{base{sp}, name}, nil
+}
+
+// nested runs f inside brackets, where line
+// breaks are whitespaces, up to the close.
+func (p *parser) nested(f func() error) error {
+	p.nest++
+	p.ws(true)
+	err := f()
+	p.ws(true)
+	p.nest--
+	return err
+}
+
+// call parses the arguments of a function call.
+func (p *parser) call(name string, at scanner.Scanner) (Expr, error) {
+	p.s.Next()
+	c := &Call{Name: name}
+	err := p.list(')', ErrParen, func() error {
+		a, err := p.expr()
+		if err != nil {
+			return err
+		}
+		c.Args = append(c.Args, a)
+		p.ws(true)
+		if p.s.Match("...") {
+			c.Expand = true
+			p.ws(true)
+			if !p.s.EqualByte(')') {
+				return p.unexpected(ErrParen)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.span = p.src.Span(at, p.s)
+	return c, nil
+}
+
+// tuple parses a list.
+func (p *parser) tuple() (Expr, error) {
+	at := p.s
+	p.s.Next()
+	t := &Tuple{}
+	err := p.list(']', ErrBracket, func() error {
+		x, err := p.expr()
+		t.Items = append(t.Items, x)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	t.span = p.src.Span(at, p.s)
+	return t, nil
+}
+
+// list parses comma separated items up to end. A
+// trailing comma is fine. Line breaks are whitespaces.
+func (p *parser) list(end byte, errEnd error, item func() error) error {
+	p.nest++
+	defer func() { p.nest-- }()
+	for {
+		p.ws(true)
+		if p.s.MatchByte(end) {
+			return nil
+		}
+		if err := item(); err != nil {
+			return err
+		}
+		p.ws(true)
+		if !p.s.MatchByte(',') && !p.s.EqualByte(end) {
+			return p.unexpected(errEnd)
+		}
+	}
+}
+
+// object parses a map. Items are separated
+// by commas or line breaks.
+func (p *parser) object() (Expr, error) {
+	at := p.s
+	p.s.Next()
+	o := &Object{}
+	p.nest++
+	defer func() { p.nest-- }()
+	for {
+		p.ws(true)
+		if p.s.MatchByte('}') {
+			o.span = p.src.Span(at, p.s)
+			return o, nil
+		}
+		var key Expr
+		m := p.s
+		if name := p.ident(); name != "" {
+			key = &Literal{base{p.src.Span(m, p.s)}, name}
+		} else {
+			var err error
+			if key, err = p.expr(); err != nil {
+				return nil, err
+			}
+		}
+		p.ws(false)
+		if !p.s.MatchByte('=') && !p.s.MatchByte(':') {
+			return nil, p.unexpected(ErrObject)
+		}
+		p.ws(true)
+		v, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		o.Items = append(o.Items, ObjectItem{key, v})
+		if p.bol() {
+			continue
+		}
+		p.ws(false)
+		if !p.s.MatchByte(',') && !p.s.EqualByte('\n') && !p.s.EqualByte('}') {
+			return nil, p.unexpected(ErrBrace)
+		}
+	}
+}
+
+// number is the number format.
+var number = scanner.NumberFormat{Float: true}
+
+// number parses a number.
+func (p *parser) number() (Expr, error) {
+	m := p.s
+	p.s.UtilMatchNumberFormat(number)
+	v := p.s.Token(m)
+	sp := p.src.Span(m, p.s)
+	if strings.ContainsAny(v, ".eE") {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, p.errorf(m, ErrNumber)
+		}
+		return &Literal{base{sp}, f}, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil, p.errorf(m, ErrNumber)
+	}
+	return &Literal{base{sp}, n}, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// #endregion Expr
+
+// #region Template
+
+// template parses a quoted string.
+func (p *parser) template() (*Template, error) {
+	at := p.s
+	p.s.Next()
+	t := &Template{}
+	if err := p.parts(t, at, -1); err != nil {
+		return nil, err
+	}
+	t.span = p.src.Span(at, p.s)
+	return t, nil
+}
+
+// heredoc parses a <<EOF heredoc or a <<-EOF one,
+// whose lines lose their common indentation.
+func (p *parser) heredoc() (*Template, error) {
+	at := p.s
+	var h scanner.Heredoc
+	if !p.s.UtilMatchHeredocStart(&h) || h.Quoted || !p.s.MatchByte('\n') && !p.s.Match("\r\n") {
+		return nil, p.errorf(at, ErrHeredoc)
+	}
+	h.Indent, h.Strip = h.Indent || h.Strip, false
+	body := p.s
+	if !p.s.UtilMatchHeredocBody(&h) {
+		return nil, p.errorf(at, ErrHeredoc)
+	}
+	end := p.s
+	p.s = body
+	t := &Template{Heredoc: true}
+	if err := p.parts(t, at, len(body)-len(h.Body)); err != nil {
+		return nil, err
+	}
+	if h.Indent {
+		dedent(t, h)
+	}
+	p.s = end
+	// The span ends at the terminator.
+	t.span = p.src.Span(at, end)
+	for t.span.End > t.span.Start && (p.src[t.span.End-1] == '\n' || p.src[t.span.End-1] == '\r') {
+		t.span.End--
+	}
+	return t, nil
+}
+
+// parts parses the parts of a template up to a quote,
+// or, if stop is not negative, up to where stop bytes
+// of the input are left. Heredocs take no escapes.
+func (p *parser) parts(t *Template, at scanner.Scanner, stop int) error {
+	quoted := stop < 0
+	var b []byte
+	lit := p.s
+	flush := func() {
+		if len(b) > 0 {
+			t.Parts = append(t.Parts, &Literal{base{p.src.Span(lit, p.s)}, string(b)})
+			b = b[:0]
+		}
+	}
+	for {
+		c := p.s.Curr()
+		switch {
+		case !quoted && len(p.s) <= stop:
+			flush()
+			return nil
+		case !p.s.More() || quoted && c == '\n':
+			return p.errorf(at, ErrString)
+		case quoted && c == '"':
+			flush()
+			p.s.Next()
+			return nil
+		case quoted && c == '\\':
+			var err error
+			if b, err = p.escape(b); err != nil {
+				return err
+			}
+		case p.s.Match("$${"):
+			b = append(b, "${"...)
+		case p.s.Match("%%{"):
+			b = append(b, "%{"...)
+		case p.s.Equal("${"):
+			flush()
+			p.s.Advance(2)
+			var x Expr
+			err := p.nested(func() (err error) {
+				x, err = p.expr()
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			if !p.s.MatchByte('}') {
+				return p.unexpected(ErrBrace)
+			}
+			t.Parts = append(t.Parts, x)
+			lit = p.s
+		default:
+			b = append(b, c)
+			p.s.Next()
+		}
+	}
+}
+
+// escape decodes an escape sequence into b.
+func (p *parser) escape(b []byte) ([]byte, error) {
+	ini := p.s
+	p.s.Next()
+	c := p.s.Curr()
+	p.s.Next()
+	switch c {
+	case 'n':
+		return append(b, '\n'), nil
+	case 'r':
+		return append(b, '\r'), nil
+	case 't':
+		return append(b, '\t'), nil
+	case '"', '\\':
+		return append(b, c), nil
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		r, ok := p.s.UtilParseHexRune(n)
+		if !ok {
+			return nil, p.errorf(ini, ErrEscape)
+		}
+		return utf8.AppendRune(b, r), nil
+	}
+	return nil, p.errorf(ini, ErrEscape)
+}
+
+// dedent removes the common indentation of the
+// lines of a heredoc from its literal parts.
+func dedent(t *Template, h scanner.Heredoc) {
+	n := -1
+	for _, line := range strings.SplitAfter(h.Body, "\n") {
+		text := strings.TrimLeft(line, " \t")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if k := len(line) - len(text); n < 0 || k < n {
+			n = k
+		}
+	}
+	if n <= 0 {
+		return
+	}
+	bol := true
+	for _, v := range t.Parts {
+		l, ok := v.(*Literal)
+		if !ok {
+			bol = false
+			continue
+		}
+		var b strings.Builder
+		s := l.Value.(string)
+		for i := 0; i < len(s); i++ {
+			if bol {
+				k := 0
+				for k < n && i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+					k, i = k+1, i+1
+				}
+				if i == len(s) {
+					break
+				}
+			}
+			b.WriteByte(s[i])
+			bol = s[i] == '\n'
+		}
+		l.Value = b.String()
+	}
+}
+
+// #endregion Template
@@ -0,0 +1,201 @@
+package hcl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// #region Expr
+
+func TestParseExpr(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+	}{
+		{give: "1", exp: "1"},
+		{give: "1.5e2", exp: "150"},
+		{give: "true", exp: "true"},
+		{give: "null", exp: "null"},
+		{give: "x", exp: "x"},
+		{give: "1 + 2 * 3", exp: "(+ 1 (* 2 3))"},
+		{give: "(1 + 2) * 3", exp: "(* (paren (+ 1 2)) 3)"},
+		{give: "1 - 2 - 3", exp: "(- (- 1 2) 3)"},
+		{give: "a || b && c", exp: "(|| a (&& b c))"},
+		{give: "a == 1 && b >= 2", exp: "(&& (== a 1) (>= b 2))"},
+		{give: "a < b != c", exp: "(!= (< a b) c)"},
+		{give: "-a * !b", exp: "(* (- a) (! b))"},
+		{give: "a % 2 / 3", exp: "(/ (% a 2) 3)"},
+		{give: "a ? b : c ? d : e", exp: "(? a b (? c d e))"},
+		{give: "n > 1 ? n : 1", exp: "(? (> n 1) n 1)"},
+		{give: "var.a.b", exp: "(. (. var a) b)"},
+		{give: "a[0].b", exp: "(. ([] a 0) b)"},
+		{give: "a.0", exp: "([] a 0)"},
+		{give: "a[\n  i + 1\n]", exp: "([] a (+ i 1))"},
+		{give: "max(1, 2)", exp: "(call max 1 2)"},
+		{give: "f()", exp: "(call f)"},
+		{give: "f(\n  a,\n  b,\n)", exp: "(call f a b)"},
+		{give: "min(list...)", exp: "(call min list ...)"},
+		{give: "[1, [2], []]", exp: "[1 [2] []]"},
+		{give: "[\n  1,\n  2,\n]", exp: "[1 2]"},
+		{give: `{ a = 1, "b" : 2 }`, exp: `{"a"=1 (tpl "b")=2}`},
+		{give: "{\n  a = 1\n  b = [2]\n}", exp: `{"a"=1 "b"=[2]}`},
+		{give: "{ (k) = v }", exp: "{(paren k)=v}"},
+		{give: `"a\tb\"é"`, exp: `(tpl "a\tb\"é")`},
+		{give: `"a ${x + 1} b"`, exp: `(tpl "a " (+ x 1) " b")`},
+		{give: `"$${x} %%{y}"`, exp: `(tpl "${x} %{y}")`},
+		{give: `"${"${x}"}"`, exp: "(tpl (tpl x))"},
+		{give: `""`, exp: "(tpl)"},
+		{give: "<<EOT\n  a\n  b\nEOT", exp: `(heredoc "  a\n  b\n")`},
+		{give: "<<-EOT\n    a\n      b ${c}\n  EOT", exp: `(heredoc "a\n  b " c "\n")`},
+		{give: "<<EOT\n\\n ${x}\nEOT", exp: `(heredoc "\\n " x "\n")`},
+		{give: "# c\n1 /* c */ + // c\n 2\n", exp: "(+ 1 2)"},
+	}
+	for _, tc := range tt {
+		x, err := ParseExpr(tc.give)
+		if assertEqual(t, nil, err, tc.give) {
+			assertEqual(t, tc.exp, sexpr(x), tc.give)
+		}
+	}
+}
+
+func TestParseExprSpan(t *testing.T) {
+	src := `a + f(b, "c${d}")`
+	x, err := ParseExpr(src)
+	assertEqual(t, nil, err)
+	bin := x.(*Binary)
+	assertEqual(t, `a + f(b, "c${d}")`, bin.Span().Text(src))
+	assertEqual(t, "a", bin.X.Span().Text(src))
+	call := bin.Y.(*Call)
+	assertEqual(t, `f(b, "c${d}")`, call.Span().Text(src))
+	tpl := call.Args[1].(*Template)
+	assertEqual(t, `"c${d}"`, tpl.Span().Text(src))
+	assertEqual(t, "c", tpl.Parts[0].Span().Text(src))
+	assertEqual(t, "d", tpl.Parts[1].Span().Text(src))
+	assertEqual(t, 13, tpl.Parts[1].Span().Start)
+}
+
+func TestTemplateLiteral(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+		ok   bool
+	}{
+		{give: `"a\nb"`, exp: "a\nb", ok: true},
+		{give: `""`, exp: "", ok: true},
+		{give: `"$${a}"`, exp: "${a}", ok: true},
+		{give: `"a${b}"`, exp: "", ok: false},
+	}
+	for _, tc := range tt {
+		x, err := ParseExpr(tc.give)
+		assertEqual(t, nil, err, tc.give)
+		v, ok := x.(*Template).Literal()
+		assertEqual(t, tc.exp, v, tc.give)
+		assertEqual(t, tc.ok, ok, tc.give)
+	}
+}
+
+func TestParseExprError(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+		err  error
+	}{
+		{give: "", exp: "hcl: expected an expression at 1:1", err: ErrExpr},
+		{give: "1 +", exp: "hcl: expected an expression at 1:4", err: ErrExpr},
+		{give: "*", exp: "hcl: expected an expression at 1:1", err: ErrExpr},
+		{give: "1 2", exp: "hcl: unexpected text after expression at 1:3", err: ErrTrailing},
+		{give: "(1", exp: "hcl: expected ')' at 1:3", err: ErrParen},
+		{give: "f(1 2)", exp: "hcl: expected ')' at 1:5", err: ErrParen},
+		{give: "f(a..., b)", exp: "hcl: expected ')' at 1:7", err: ErrParen},
+		{give: "[1 2]", exp: "hcl: expected ']' at 1:4", err: ErrBracket},
+		{give: "a[1", exp: "hcl: expected ']' at 1:4", err: ErrBracket},
+		{give: "{a = 1 b = 2}", exp: "hcl: expected '}' at 1:8", err: ErrBrace},
+		{give: "{a 1}", exp: "hcl: expected '=' or ':' after object key at 1:4", err: ErrObject},
+		{give: "{a = 1", exp: "hcl: expected '}' at 1:7", err: ErrBrace},
+		{give: "a ? b", exp: "hcl: expected ':' in conditional at 1:6", err: ErrColon},
+		{give: "a.", exp: "hcl: expected an identifier at 1:3", err: ErrIdent},
+		{give: "a.99999999999999999999", exp: "hcl: invalid number at 1:3", err: ErrNumber},
+		{give: "99999999999999999999", exp: "hcl: invalid number at 1:1", err: ErrNumber},
+		{give: `"${x"`, exp: "hcl: expected '}' at 1:5", err: ErrBrace},
+		{give: `"\u12"`, exp: "hcl: invalid escape at 1:2", err: ErrEscape},
+		{give: `"\ud800"`, exp: "hcl: invalid escape at 1:2", err: ErrEscape},
+		{give: "<<'EOT'\nx\nEOT", exp: "hcl: invalid heredoc at 1:1", err: ErrHeredoc},
+		{give: "<<EOT x\nEOT", exp: "hcl: invalid heredoc at 1:1", err: ErrHeredoc},
+		{give: "1 /* x", exp: "hcl: unclosed comment at 1:3", err: ErrComment},
+	}
+	for _, tc := range tt {
+		_, err := ParseExpr(tc.give)
+		if assertEqual(t, true, err != nil, tc.give) {
+			assertEqual(t, tc.exp, err.Error(), tc.give)
+			assertEqual(t, true, errors.Is(err, tc.err), tc.give)
+		}
+	}
+}
+
+func BenchmarkParseExpr(b *testing.B) {
+	src := `length(var.list) > 2 ? "${var.name}-${upper(var.env)}" : join(",", [for_each, 1 + 2 * 3])`
+	for i := 0; i < b.N; i++ {
+		ParseExpr(src)
+	}
+}
+
+// #endregion Expr
+
+// sexpr prints an expression as an s-expression.
+func sexpr(x Expr) string {
+	list := func(head string, xs ...Expr) string {
+		s := []string{head}
+		for _, v := range xs {
+			s = append(s, sexpr(v))
+		}
+		return "(" + strings.Join(s, " ") + ")"
+	}
+	switch x := x.(type) {
+	case *Literal:
+		switch v := x.Value.(type) {
+		case string:
+			return fmt.Sprintf("%q", v)
+		case nil:
+			return "null"
+		default:
+			return fmt.Sprint(v)
+		}
+	case *Template:
+		if x.Heredoc {
+			return list("heredoc", x.Parts...)
+		}
+		return list("tpl", x.Parts...)
+	case *Variable:
+		return x.Name
+	case *GetAttr:
+		return "(. " + sexpr(x.X) + " " + x.Name + ")"
+	case *Index:
+		return list("[]", x.X, x.Key)
+	case *Call:
+		s := list("call "+x.Name, x.Args...)
+		if x.Expand {
+			s = s[:len(s)-1] + " ...)"
+		}
+		return s
+	case *Unary:
+		return list(x.Op, x.X)
+	case *Binary:
+		return list(x.Op, x.X, x.Y)
+	case *Conditional:
+		return list("?", x.Cond, x.True, x.False)
+	case *Paren:
+		return list("paren", x.X)
+	case *Tuple:
+		s := list("", x.Items...)
+		return "[" + strings.TrimPrefix(s[1:len(s)-1], " ") + "]"
+	case *Object:
+		var s []string
+		for _, v := range x.Items {
+			s = append(s, sexpr(v.Key)+"="+sexpr(v.Value))
+		}
+		return "{" + strings.Join(s, " ") + "}"
+	}
+	return fmt.Sprintf("%T", x)
+}
@@ -0,0 +1,273 @@
+// Package hcl parses HCL-style configurations with the
+// scanner into a syntax tree with spans. Bodies have
+// attributes and labeled blocks, and attribute values
+// are expressions with lists, maps, heredocs, ${}
+// interpolation, arithmetic, conditionals and calls.
+package hcl
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/ofabricio/scanner"
+)
+
+// #region Errors
+
+var (
+	ErrIdent     = errors.New("expected an identifier")
+	ErrBlock     = errors.New("expected '=' or a block")
+	ErrLabel     = errors.New("invalid block label")
+	ErrNewline   = errors.New("expected a line break")
+	ErrExpr      = errors.New("expected an expression")
+	ErrBrace     = errors.New("expected '}'")
+	ErrBracket   = errors.New("expected ']'")
+	ErrParen     = errors.New("expected ')'")
+	ErrColon     = errors.New("expected ':' in conditional")
+	ErrObject    = errors.New("expected '=' or ':' after object key")
+	ErrString    = errors.New("unclosed string")
+	ErrEscape    = errors.New("invalid escape")
+	ErrHeredoc   = errors.New("invalid heredoc")
+	ErrComment   = errors.New("unclosed comment")
+	ErrNumber    = errors.New("invalid number")
+	ErrDuplicate = errors.New("duplicate attribute")
+	ErrTrailing  = errors.New("unexpected text after expression")
+)
+
+// ParseError is a positioned error.
+// Key is the name of ErrDuplicate errors.
+type ParseError = scanner.ParseError
+
+// #endregion Errors
+
+// #region Body
+
+// Body is the attributes and blocks of a
+// file or of a block, in source order.
+type Body struct {
+	Attributes []*Attribute
+	Blocks     []*Block
+	Span       scanner.Span
+}
+
+// Attribute is a name = expression definition.
+type Attribute struct {
+	Name     string
+	Expr     Expr
+	NameSpan scanner.Span
+	Span     scanner.Span
+}
+
+// Block is a block, like:
+//
+//	resource "aws_instance" "web" {
+//	  ami = "abc"
+//	}
+type Block struct {
+	Type       string
+	Labels     []string
+	Body       *Body
+	TypeSpan   scanner.Span
+	LabelSpans []scanner.Span
+	Span       scanner.Span
+}
+
+// Attribute returns the attribute of a name, or nil.
+func (b *Body) Attribute(name string) *Attribute {
+	for _, a := range b.Attributes {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// BlocksOf returns the blocks of a type.
+func (b *Body) BlocksOf(typ string) []*Block {
+	var list []*Block
+	for _, v := range b.Blocks {
+		if v.Type == typ {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// Parse parses a configuration. Comments are #, // and /* */.
+func Parse(src string) (*Body, error) {
+	p := parser{src: scanner.Scanner(src), s: scanner.Scanner(src)}
+	return p.body(false)
+}
+
+type parser struct {
+	src, s scanner.Scanner
+	// nest is the depth of brackets, where
+	// line breaks are whitespaces.
+	nest int
+}
+
+// body parses attributes and blocks up to
+// a '}', if inner, or the end of the input.
+func (p *parser) body(inner bool) (*Body, error) {
+	b := &Body{}
+	ini := p.s
+	for {
+		p.ws(true)
+		if !p.s.More() {
+			if inner {
+				return nil, p.errorf(p.s, ErrBrace)
+			}
+			break
+		}
+		if inner && p.s.EqualByte('}') {
+			break
+		}
+		at := p.s
+		name := p.ident()
+		if name == "" {
+			return nil, p.unexpected(ErrIdent)
+		}
+		nameSpan := p.src.Span(at, p.s)
+		p.ws(false)
+		if p.s.MatchByte('=') {
+			if a := b.Attribute(name); a != nil {
+				line, col := p.src.LineCol(nameSpan.Start)
+				return nil, &ParseError{Line: line, Col: col, Err: ErrDuplicate, Pkg: "hcl", Key: name}
+			}
+			p.ws(false)
+			x, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			b.Attributes = append(b.Attributes, &Attribute{
+				Name: name, Expr: x, NameSpan: nameSpan, Span: nameSpan.Merge(x.Span()),
+			})
+		} else {
+			blk, err := p.block(name, nameSpan)
+			if err != nil {
+				return nil, err
+			}
+			b.Blocks = append(b.Blocks, blk)
+		}
+		if err := p.eol(); err != nil {
+			return nil, err
+		}
+	}
+	b.Span = p.src.Span(ini, p.s)
+	return b, nil
+}
+
+// block parses the labels and the body of a block.
+func (p *parser) block(typ string, typSpan scanner.Span) (*Block, error) {
+	b := &Block{Type: typ, TypeSpan: typSpan}
+	for !p.s.MatchByte('{') {
+		m := p.s
+		var label string
+		if p.s.EqualByte('"') {
+			t, err := p.template()
+			if err != nil {
+				return nil, err
+			}
+			v, ok := t.Literal()
+			if !ok {
+				return nil, p.errorf(m, ErrLabel)
+			}
+			label = v
+		} else if label = p.ident(); label == "" {
+			return nil, p.unexpected(ErrBlock)
+		}
+		b.Labels = append(b.Labels, label)
+		b.LabelSpans = append(b.LabelSpans, p.src.Span(m, p.s))
+		p.ws(false)
+	}
+	body, err := p.body(true)
+	if err != nil {
+		return nil, err
+	}
+	p.s.Next()
+	b.Body, b.Span = body, scanner.Span{Start: typSpan.Start, End: p.off(p.s)}
+	return b, nil
+}
+
+// eol matches the end of an attribute or a block: a line
+// break, the end of the input or of the enclosing block.
+// A heredoc ends past its line break.
+func (p *parser) eol() error {
+	if p.bol() {
+		return nil
+	}
+	p.ws(false)
+	if p.s.MatchByte('\n') || !p.s.More() || p.s.EqualByte('}') {
+		return nil
+	}
+	return p.unexpected(ErrNewline)
+}
+
+// bol tells if the scanner is at the beginning of a line.
+func (p *parser) bol() bool {
+	off := p.off(p.s)
+	return off > 0 && p.src[off-1] == '\n'
+}
+
+// ws skips whitespaces and comments. It skips
+// line breaks too if nl is true. An unclosed
+// comment is left for the caller to fail on.
+func (p *parser) ws(nl bool) {
+	for {
+		p.s.MatchWhileByteBy(func(c byte) bool {
+			return c == ' ' || c == '\t' || c == '\r' || nl && c == '\n'
+		})
+		switch {
+		case p.s.EqualByte('#') || p.s.Equal("//"):
+			if !p.s.MatchUntilByte('\n') {
+				p.s.Advance(len(p.s))
+			}
+		case p.s.Equal("/*"):
+			i := strings.Index(p.s[2:].String(), "*/")
+			if i < 0 {
+				return
+			}
+			p.s.Advance(i + 4)
+		default:
+			return
+		}
+	}
+}
+
+// ident is an identifier. They can have dashes.
+var ident = scanner.NewIdent(isIdentStart, isIdentContinue)
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentContinue(r rune) bool {
+	return isIdentStart(r) || r == '-' || unicode.IsDigit(r)
+}
+
+func (p *parser) ident() string {
+	m := p.s
+	p.s.UtilMatchIdent(ident)
+	return p.s.Token(m)
+}
+
+func (p *parser) off(at scanner.Scanner) int {
+	return len(p.src) - len(at)
+}
+
+func (p *parser) errorf(at scanner.Scanner, err error) error {
+	line, col := p.src.LineCol(p.off(at))
+	return &ParseError{Line: line, Col: col, Err: err, Pkg: "hcl"}
+}
+
+// unexpected returns err at the current position,
+// or ErrComment if an unclosed comment is there.
+func (p *parser) unexpected(err error) error {
+	if p.s.Equal("/*") {
+		err = ErrComment
+	}
+	return p.errorf(p.s, err)
+}
+
+// #endregion Body
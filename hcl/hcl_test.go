@@ -0,0 +1,119 @@
+package hcl
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// #region Parse
+
+func TestParse(t *testing.T) {
+	src := `# servers
+region = "eu"
+
+resource "aws_instance" web {
+  ami   = "abc" // image
+  count = 2
+  tags {
+    name = "web-${count}"
+  }
+}
+
+/* empty */ empty {}
+`
+	b, err := Parse(src)
+	assertEqual(t, nil, err)
+	assertEqual(t, 1, len(b.Attributes))
+	assertEqual(t, 2, len(b.Blocks))
+	region := b.Attribute("region")
+	assertEqual(t, "region", region.Name)
+	assertEqual(t, `region = "eu"`, region.Span.Text(src))
+	assertEqual(t, "region", region.NameSpan.Text(src))
+	assertEqual(t, `(tpl "eu")`, sexpr(region.Expr))
+
+	res := b.BlocksOf("resource")
+	assertEqual(t, 1, len(res))
+	r := res[0]
+	assertEqual(t, []string{"aws_instance", "web"}, r.Labels)
+	assertEqual(t, "resource", r.TypeSpan.Text(src))
+	assertEqual(t, `"aws_instance"`, r.LabelSpans[0].Text(src))
+	assertEqual(t, "web", r.LabelSpans[1].Text(src))
+	assertEqual(t, true, r.Span.Text(src)[:10] == "resource \"")
+	assertEqual(t, byte('}'), r.Span.Text(src)[r.Span.Len()-1])
+	assertEqual(t, "2", sexpr(r.Body.Attribute("count").Expr))
+	assertEqual(t, `(tpl "web-" count)`, sexpr(r.Body.BlocksOf("tags")[0].Body.Attribute("name").Expr))
+	assertEqual(t, (*Attribute)(nil), r.Body.Attribute("nope"))
+
+	empty := b.BlocksOf("empty")[0]
+	assertEqual(t, "empty {}", empty.Span.Text(src))
+	assertEqual(t, 0, len(empty.Labels))
+	assertEqual(t, 0, len(empty.Body.Attributes)+len(empty.Body.Blocks))
+}
+
+func TestParseHeredoc(t *testing.T) {
+	src := "doc = <<-EOT\n    hello\n      ${name}\n    EOT\n  b = 1\n"
+	b, err := Parse(src)
+	assertEqual(t, nil, err)
+	doc := b.Attribute("doc")
+	assertEqual(t, `(heredoc "hello\n  " name "\n")`, sexpr(doc.Expr))
+	assertEqual(t, "doc = <<-EOT\n    hello\n      ${name}\n    EOT", doc.Span.Text(src))
+	assertEqual(t, "1", sexpr(b.Attribute("b").Expr))
+}
+
+func TestParseEmpty(t *testing.T) {
+	for _, give := range []string{"", "\n", "# only", "// a\n/* b */\n"} {
+		b, err := Parse(give)
+		assertEqual(t, nil, err, give)
+		assertEqual(t, 0, len(b.Attributes)+len(b.Blocks), give)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+		err  error
+	}{
+		{give: "a = 1 2", exp: "hcl: expected a line break at 1:7", err: ErrNewline},
+		{give: "a = 1\na = 2", exp: `hcl: duplicate attribute "a" at 2:1`, err: ErrDuplicate},
+		{give: "= 1", exp: "hcl: expected an identifier at 1:1", err: ErrIdent},
+		{give: "a 1", exp: "hcl: expected '=' or a block at 1:3", err: ErrBlock},
+		{give: `a "${x}" {}`, exp: "hcl: invalid block label at 1:3", err: ErrLabel},
+		{give: "b {\n a = 1", exp: "hcl: expected '}' at 2:7", err: ErrBrace},
+		{give: "b { a = 1 c = 2 }", exp: "hcl: expected a line break at 1:11", err: ErrNewline},
+		{give: "a =", exp: "hcl: expected an expression at 1:4", err: ErrExpr},
+		{give: "a = \"x", exp: "hcl: unclosed string at 1:5", err: ErrString},
+		{give: "a = \"x\ny\"", exp: "hcl: unclosed string at 1:5", err: ErrString},
+		{give: `a = "\q"`, exp: "hcl: invalid escape at 1:6", err: ErrEscape},
+		{give: "a = <<EOT\nx", exp: "hcl: invalid heredoc at 1:5", err: ErrHeredoc},
+		{give: "/* x", exp: "hcl: unclosed comment at 1:1", err: ErrComment},
+		{give: "a = 1 /* x", exp: "hcl: unclosed comment at 1:7", err: ErrComment},
+	}
+	for _, tc := range tt {
+		_, err := Parse(tc.give)
+		if assertEqual(t, true, err != nil, tc.give) {
+			assertEqual(t, tc.exp, err.Error(), tc.give)
+			assertEqual(t, true, errors.Is(err, tc.err), tc.give)
+		}
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	src := "variable \"region\" {\n  default = \"eu\"\n}\n\nresource \"instance\" \"web\" {\n  count = var.n > 1 ? var.n : 1\n  tags  = { name = \"web-${count.index}\" }\n  ports = [80, 443]\n}\n"
+	for i := 0; i < b.N; i++ {
+		Parse(src)
+	}
+}
+
+// #endregion Parse
+
+func assertEqual(t *testing.T, exp, got any, msgs ...any) bool {
+	t.Helper()
+	if !reflect.DeepEqual(exp, got) {
+		t.Errorf("\nExp:\n%v\nGot:\n%v\nMsg: %v", exp, got, fmt.Sprint(msgs...))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,308 @@
+// Package highlight renders code as HTML or ANSI
+// colored text with lexers built on the scanner.
+package highlight
+
+import (
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ofabricio/scanner"
+)
+
+// #region Kinds
+
+// Token kinds of the built-in lexers.
+var (
+	Comment         = scanner.NewKind("Comment")
+	Keyword         = scanner.NewKind("Keyword")
+	KeywordConstant = scanner.NewKind("KeywordConstant") // Like true and null.
+	KeywordType     = scanner.NewKind("KeywordType")     // Like int and VARCHAR.
+	Name            = scanner.NewKind("Name")
+	NameBuiltin     = scanner.NewKind("NameBuiltin")  // Like len and echo.
+	NameVariable    = scanner.NewKind("NameVariable") // Like $HOME.
+	NameTag         = scanner.NewKind("NameTag")      // Like JSON keys.
+	String          = scanner.NewKind("String")
+	Number          = scanner.NewKind("Number")
+	Operator        = scanner.NewKind("Operator")
+	Punct           = scanner.NewKind("Punct")
+)
+
+// Classes maps the token kinds to style classes.
+// The names are the short ones of Pygments, so
+// its stylesheets work with the HTML output.
+var Classes = map[scanner.Kind]string{
+	Comment:         "c",
+	Keyword:         "k",
+	KeywordConstant: "kc",
+	KeywordType:     "kt",
+	Name:            "n",
+	NameBuiltin:     "nb",
+	NameVariable:    "nv",
+	NameTag:         "nt",
+	String:          "s",
+	Number:          "m",
+	Operator:        "o",
+	Punct:           "p",
+}
+
+// #endregion Kinds
+
+// #region Lang
+
+// Lang is a language lexer.
+type Lang struct {
+	Name    string
+	Aliases []string
+	// State is the start state of the lexer. States don't
+	// emit the text they don't style, like whitespaces,
+	// and never fail, so a snippet with syntax errors
+	// is still highlighted.
+	State scanner.StateFn
+}
+
+// Langs is the built-in languages.
+var Langs = []*Lang{JSON, Go, Shell, SQL}
+
+// Find returns the built-in language with
+// a name or alias, ignoring case, or nil.
+func Find(name string) *Lang {
+	for _, l := range Langs {
+		if strings.EqualFold(l.Name, name) {
+			return l
+		}
+		for _, a := range l.Aliases {
+			if strings.EqualFold(a, name) {
+				return l
+			}
+		}
+	}
+	return nil
+}
+
+// #endregion Lang
+
+// #region Theme
+
+// Style is the style of a class.
+type Style struct {
+	Color  string // Like "#ff8800". Empty is the default color.
+	Bold   bool
+	Italic bool
+}
+
+// Theme maps style classes to styles.
+type Theme map[string]Style
+
+// ThemeDefault is a theme for dark backgrounds.
+var ThemeDefault = Theme{
+	"c":  {Color: "#7f848e", Italic: true},
+	"k":  {Color: "#c678dd", Bold: true},
+	"kc": {Color: "#d19a66"},
+	"kt": {Color: "#e5c07b"},
+	"nb": {Color: "#56b6c2"},
+	"nv": {Color: "#e06c75"},
+	"nt": {Color: "#61afef"},
+	"s":  {Color: "#98c379"},
+	"m":  {Color: "#d19a66"},
+	"o":  {Color: "#56b6c2"},
+}
+
+// CSS returns a stylesheet of the theme. The prefix
+// goes before each class, like ".chroma ." or ".".
+func (t Theme) CSS(prefix string) string {
+	names := make([]string, 0, len(t))
+	for k := range t {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, k := range names {
+		st := t[k]
+		b.WriteString(prefix + k + " {")
+		if st.Color != "" {
+			b.WriteString(" color: " + st.Color + ";")
+		}
+		if st.Bold {
+			b.WriteString(" font-weight: bold;")
+		}
+		if st.Italic {
+			b.WriteString(" font-style: italic;")
+		}
+		b.WriteString(" }\n")
+	}
+	return b.String()
+}
+
+// #endregion Theme
+
+// #region Highlighter
+
+// Highlighter renders code of a language.
+type Highlighter struct {
+	Lang *Lang
+	// Classes maps the token kinds to style
+	// classes. Nil means the package Classes.
+	Classes map[scanner.Kind]string
+	// Prefix goes before each HTML class,
+	// like "hl-" for class="hl-k".
+	Prefix string
+	// Theme is the ANSI theme.
+	// Nil means ThemeDefault.
+	Theme Theme
+	// TrueColor renders ANSI with 24-bit colors,
+	// otherwise with the 256-color palette.
+	TrueColor bool
+}
+
+// HTML returns src as HTML, with a span per styled
+// token. The caller wraps it, usually in a <pre>.
+func (h *Highlighter) HTML(src string) string {
+	var b strings.Builder
+	h.render(src, func(text, class string) {
+		if class == "" {
+			b.WriteString(html.EscapeString(text))
+			return
+		}
+		b.WriteString(`<span class="` + h.Prefix + class + `">`)
+		b.WriteString(html.EscapeString(text))
+		b.WriteString(`</span>`)
+	})
+	return b.String()
+}
+
+// ANSI returns src with ANSI escape codes for terminals.
+func (h *Highlighter) ANSI(src string) string {
+	theme := h.Theme
+	if theme == nil {
+		theme = ThemeDefault
+	}
+	var b strings.Builder
+	h.render(src, func(text, class string) {
+		st, ok := theme[class]
+		if !ok {
+			b.WriteString(text)
+			return
+		}
+		codes := h.sgr(st)
+		if codes == "" {
+			b.WriteString(text)
+			return
+		}
+		// Line by line, so a multiline token
+		// doesn't color the pager's margins.
+		for i := 0; ; i++ {
+			line := text
+			n := strings.IndexByte(text, '\n')
+			if n >= 0 {
+				line = text[:n]
+			}
+			if line != "" {
+				b.WriteString("\x1b[" + codes + "m" + line + "\x1b[0m")
+			}
+			if n < 0 {
+				break
+			}
+			b.WriteByte('\n')
+			text = text[n+1:]
+		}
+	})
+	return b.String()
+}
+
+// render calls f with the text of src in order, with
+// the class of the styled tokens and "" otherwise.
+func (h *Highlighter) render(src string, f func(text, class string)) {
+	classes := h.Classes
+	if classes == nil {
+		classes = Classes
+	}
+	pos := 0
+	l := scanner.NewLexer(src, h.Lang.State)
+	for t := l.NextToken(); t.Kind != scanner.KindEOF; t = l.NextToken() {
+		if t.Kind == scanner.KindError || t.Span.Start < pos {
+			continue
+		}
+		if pos < t.Span.Start {
+			f(src[pos:t.Span.Start], "")
+		}
+		f(t.Text, classes[t.Kind])
+		pos = t.Span.End
+	}
+	if pos < len(src) {
+		f(src[pos:], "")
+	}
+}
+
+// sgr returns the ANSI SGR codes of a style.
+func (h *Highlighter) sgr(st Style) string {
+	var codes []string
+	if st.Bold {
+		codes = append(codes, "1")
+	}
+	if st.Italic {
+		codes = append(codes, "3")
+	}
+	if r, g, b, ok := parseColor(st.Color); ok {
+		if h.TrueColor {
+			codes = append(codes, "38;2;"+strconv.Itoa(r)+";"+strconv.Itoa(g)+";"+strconv.Itoa(b))
+		} else {
+			codes = append(codes, "38;5;"+strconv.Itoa(ansi256(r, g, b)))
+		}
+	}
+	return strings.Join(codes, ";")
+}
+
+// parseColor parses a #rrggbb color.
+func parseColor(c string) (r, g, b int, ok bool) {
+	if len(c) != 7 || c[0] != '#' {
+		return 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(c[1:], 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return int(v >> 16), int(v >> 8 & 0xff), int(v & 0xff), true
+}
+
+// ansi256 returns the nearest color of the xterm 256-color
+// palette, either from the 6x6x6 cube or the gray ramp.
+func ansi256(r, g, b int) int {
+	cube := func(v int) int {
+		if v < 48 {
+			return 0
+		}
+		if v < 115 {
+			return 1
+		}
+		return (v - 35) / 40
+	}
+	level := func(i int) int {
+		if i == 0 {
+			return 0
+		}
+		return 55 + i*40
+	}
+	dist := func(x, y, z int) int {
+		return (r-x)*(r-x) + (g-y)*(g-y) + (b-z)*(b-z)
+	}
+	cr, cg, cb := cube(r), cube(g), cube(b)
+	c := 16 + 36*cr + 6*cg + cb
+	cd := dist(level(cr), level(cg), level(cb))
+	// Gray ramp from 8 to 238 in steps of 10.
+	gi := ((r+g+b)/3 - 3) / 10
+	if gi < 0 {
+		gi = 0
+	}
+	if gi > 23 {
+		gi = 23
+	}
+	gv := 8 + gi*10
+	if dist(gv, gv, gv) < cd {
+		return 232 + gi
+	}
+	return c
+}
+
+// #endregion Highlighter
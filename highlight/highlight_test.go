@@ -0,0 +1,127 @@
+package highlight
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ofabricio/scanner"
+)
+
+// #region Highlighter
+
+func TestHighlighterHTML(t *testing.T) {
+	tt := []struct {
+		give string
+		when Highlighter
+		exp  string
+	}{
+		{
+			give: `{"a": 1}`,
+			when: Highlighter{Lang: JSON},
+			exp:  `<span class="p">{</span><span class="nt">&#34;a&#34;</span><span class="p">:</span> <span class="m">1</span><span class="p">}</span>`,
+		},
+		{
+			give: `a < b`,
+			when: Highlighter{Lang: Go, Prefix: "hl-"},
+			exp:  `<span class="hl-n">a</span> <span class="hl-o">&lt;</span> <span class="hl-n">b</span>`,
+		},
+		{
+			give: `x = 1`,
+			when: Highlighter{Lang: Go, Classes: map[scanner.Kind]string{Number: "num"}},
+			exp:  `x = <span class="num">1</span>`,
+		},
+		{
+			give: "",
+			when: Highlighter{Lang: Go},
+			exp:  "",
+		},
+	}
+	for _, tc := range tt {
+		assertEqual(t, tc.exp, tc.when.HTML(tc.give), tc.give)
+	}
+}
+
+func TestHighlighterANSI(t *testing.T) {
+	theme := Theme{
+		"k": {Color: "#ff0000", Bold: true},
+		"c": {Italic: true},
+		"n": {},
+	}
+	tt := []struct {
+		give string
+		when Highlighter
+		exp  string
+	}{
+		{
+			give: `if x`,
+			when: Highlighter{Lang: Go, Theme: theme, TrueColor: true},
+			exp:  "\x1b[1;38;2;255;0;0mif\x1b[0m x",
+		},
+		{
+			give: `if x`,
+			when: Highlighter{Lang: Go, Theme: theme},
+			exp:  "\x1b[1;38;5;196mif\x1b[0m x",
+		},
+		{
+			give: "/* a\nb */",
+			when: Highlighter{Lang: Go, Theme: theme},
+			exp:  "\x1b[3m/* a\x1b[0m\n\x1b[3mb */\x1b[0m",
+		},
+	}
+	for _, tc := range tt {
+		assertEqual(t, tc.exp, tc.when.ANSI(tc.give), tc.give)
+	}
+}
+
+func TestThemeCSS(t *testing.T) {
+	theme := Theme{
+		"k": {Color: "#ff0000", Bold: true},
+		"c": {Italic: true},
+	}
+	exp := ".hl .c { font-style: italic; }\n" +
+		".hl .k { color: #ff0000; font-weight: bold; }\n"
+	assertEqual(t, exp, theme.CSS(".hl ."))
+}
+
+func TestFind(t *testing.T) {
+	assertEqual(t, Go, Find("go"))
+	assertEqual(t, Go, Find("golang"))
+	assertEqual(t, Shell, Find("Bash"))
+	assertEqual(t, SQL, Find("sql"))
+	assertEqual(t, (*Lang)(nil), Find("cobol"))
+}
+
+func TestANSI256(t *testing.T) {
+	tt := []struct {
+		give [3]int
+		exp  int
+	}{
+		{give: [3]int{0, 0, 0}, exp: 16},
+		{give: [3]int{255, 255, 255}, exp: 231},
+		{give: [3]int{255, 0, 0}, exp: 196},
+		{give: [3]int{0, 95, 135}, exp: 24},
+		{give: [3]int{128, 128, 128}, exp: 244},
+		{give: [3]int{8, 8, 8}, exp: 232},
+	}
+	for _, tc := range tt {
+		assertEqual(t, tc.exp, ansi256(tc.give[0], tc.give[1], tc.give[2]), tc.give)
+	}
+}
+
+func BenchmarkHighlighterHTML(b *testing.B) {
+	h := Highlighter{Lang: Go}
+	src := "func f(a []int) int { return len(a) + 0x1F // c\n}"
+	for i := 0; i < b.N; i++ {
+		h.HTML(src)
+	}
+}
+
+// #endregion Highlighter
+
+func assertEqual(t *testing.T, exp, got any, msgs ...any) {
+	t.Helper()
+	if !reflect.DeepEqual(exp, got) {
+		t.Errorf("\nExp:\n%v\nGot:\n%v\nMsg: %v", exp, got, fmt.Sprint(msgs...))
+	}
+}
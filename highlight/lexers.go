@@ -0,0 +1,416 @@
+package highlight
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ofabricio/scanner"
+)
+
+// Built-in languages.
+var (
+	JSON  = &Lang{Name: "JSON", State: lexJSON}
+	Go    = &Lang{Name: "Go", Aliases: []string{"golang"}, State: lexGo}
+	Shell = &Lang{Name: "Shell", Aliases: []string{"sh", "bash", "zsh"}, State: lexShell}
+	SQL   = &Lang{Name: "SQL", State: lexSQL}
+)
+
+// #region JSON
+
+var jsonWords = newWords(false, map[scanner.Kind]string{
+	KeywordConstant: "true false null",
+})
+
+func lexJSON(l *scanner.Lexer) scanner.StateFn {
+	l.WS()
+	l.Ignore()
+	switch {
+	case !l.More():
+		return nil
+	case l.EqualByte('"'):
+		matchQuoted(&l.Scanner, `"`, `\`)
+		// A string followed by a colon is a key.
+		if ss := l.Scanner; ss.WS() && ss.EqualByte(':') {
+			l.Emit(NameTag)
+		} else {
+			l.Emit(String)
+		}
+	case l.UtilMatchNumber():
+		l.Emit(Number)
+	case jsonWords.match(l, scanner.IdentJS):
+	case l.MatchByte('{') || l.MatchByte('}') || l.MatchByte('[') || l.MatchByte(']') ||
+		l.MatchByte(':') || l.MatchByte(','):
+		l.Emit(Punct)
+	default:
+		l.NextRune()
+	}
+	return lexJSON
+}
+
+// #endregion JSON
+
+// #region Go
+
+var goWords = newWords(false, map[scanner.Kind]string{
+	Keyword: `break case chan const continue default defer else fallthrough
+		for func go goto if import interface map package range return
+		select struct switch type var`,
+	KeywordConstant: "true false nil iota",
+	KeywordType: `any bool byte comparable complex64 complex128 error
+		float32 float64 int int8 int16 int32 int64 rune string
+		uint uint8 uint16 uint32 uint64 uintptr`,
+	NameBuiltin: `append cap clear close complex copy delete imag len
+		make max min new panic print println real recover`,
+})
+
+var goOps = scanner.NewLiterals(
+	"+", "-", "*", "/", "%", "&", "|", "^", "<<", ">>", "&^",
+	"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "<<=", ">>=", "&^=",
+	"&&", "||", "<-", "++", "--", "==", "<", ">", "=", "!", "~",
+	"!=", "<=", ">=", ":=", "...",
+)
+
+func lexGo(l *scanner.Lexer) scanner.StateFn {
+	l.WS()
+	l.Ignore()
+	switch {
+	case !l.More():
+		return nil
+	case matchComment(&l.Scanner, &scanner.TriviaC):
+		l.Emit(Comment)
+	case l.EqualByte('"'):
+		matchQuoted(&l.Scanner, `"`, `\`)
+		l.Emit(String)
+	case l.EqualByte('\''):
+		matchQuoted(&l.Scanner, `'`, `\`)
+		l.Emit(String)
+	case l.MatchByte('`'):
+		matchUntil(&l.Scanner, "`")
+		l.Emit(String)
+	case l.UtilMatchNumberFormat(scanner.NumberGo):
+		l.Emit(Number)
+	case goWords.match(l, scanner.IdentGo):
+	case l.UtilMatchIdent(scanner.IdentGo):
+		l.Emit(Name)
+	case matchOp(&l.Scanner, goOps):
+		l.Emit(Operator)
+	case l.MatchByte('(') || l.MatchByte(')') || l.MatchByte('[') || l.MatchByte(']') ||
+		l.MatchByte('{') || l.MatchByte('}') || l.MatchByte(',') || l.MatchByte(';') ||
+		l.MatchByte('.') || l.MatchByte(':'):
+		l.Emit(Punct)
+	default:
+		l.NextRune()
+	}
+	return lexGo
+}
+
+// #endregion Go
+
+// #region Shell
+
+var shellWords = newWords(false, map[scanner.Kind]string{
+	Keyword: `if then else elif fi for while until do done case esac
+		in function select return break continue`,
+	NameBuiltin: `alias bg cd command echo eval exec exit export false
+		fg jobs kill local printf pwd read readonly set shift source
+		test trap true type ulimit umask unalias unset wait`,
+})
+
+var shellOps = scanner.NewLiterals(
+	"|", "||", "&", "&&", ";", ";;", "<", ">", ">>", "<<<",
+	"<&", ">&", "&>", "&>>", ">|", "(", ")", "{", "}", "[[", "]]", "!",
+)
+
+// shellMeta is the bytes that end a shell word.
+const shellMeta = " \t\r\n|&;<>()$`'\"\\"
+
+// shellWord is a shell word, so "echo" is a
+// keyword in "echo x" but not in "echo.sh".
+var shellWord = scanner.NewIdent(isShellWord, isShellWord)
+
+// shellName is a shell variable name.
+var shellName = scanner.NewIdent(isNameStart, isNameContinue)
+
+func isShellWord(r rune) bool {
+	return r > ' ' && !strings.ContainsRune(shellMeta, r)
+}
+
+func isNameStart(r rune) bool {
+	return r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+func isNameContinue(r rune) bool {
+	return isNameStart(r) || r >= '0' && r <= '9'
+}
+
+// lexShell starts a shell lexer. Heredoc bodies start
+// at the next line, so they are kept until there.
+func lexShell(l *scanner.Lexer) scanner.StateFn {
+	var sh shell
+	return sh.lex
+}
+
+type shell struct {
+	heredocs []scanner.Heredoc
+}
+
+func (sh *shell) lex(l *scanner.Lexer) scanner.StateFn {
+	l.MatchWhileByteBy(func(c byte) bool { return c == ' ' || c == '\t' || c == '\r' })
+	l.Ignore()
+	switch {
+	case !l.More():
+		return nil
+	case l.MatchByte('\n'):
+		l.Ignore()
+		for _, h := range sh.heredocs {
+			if !l.UtilMatchHeredocBody(&h) {
+				l.Advance(len(l.Scanner))
+			}
+			l.Emit(String)
+		}
+		sh.heredocs = sh.heredocs[:0]
+	case l.EqualByte('#'):
+		matchComment(&l.Scanner, &scanner.TriviaShell)
+		l.Emit(Comment)
+	case l.MatchByte('\\'):
+		l.NextRune()
+		l.Ignore()
+	case l.MatchByte('\''):
+		matchUntil(&l.Scanner, "'")
+		l.Emit(String)
+	case l.EqualByte('"') || l.EqualByte('`'):
+		if ok, _ := l.UtilMatchInterp(&scanner.InterpShell, nil); !ok {
+			l.Advance(len(l.Scanner))
+		}
+		l.Emit(String)
+	case l.MatchByte('$'):
+		switch {
+		case l.EqualByte('{'):
+			if !l.UtilMatchOpenCloseCount('{', '}', '"') {
+				l.Advance(len(l.Scanner))
+			}
+			l.Emit(NameVariable)
+		case l.EqualByte('('):
+			l.Emit(Operator)
+		case l.UtilMatchIdent(shellName) || l.MatchByteBy(isShellSpecial):
+			l.Emit(NameVariable)
+		default:
+			l.Ignore()
+		}
+	case sh.heredoc(l):
+		l.Emit(Operator)
+	case matchOp(&l.Scanner, shellOps):
+		l.Emit(Operator)
+	case sh.assign(l):
+	case shellWords.match(l, shellWord):
+	case l.UtilMatchIdent(shellWord):
+		l.Ignore()
+	default:
+		l.NextRune()
+	}
+	return sh.lex
+}
+
+// heredoc matches a heredoc operator, like <<EOF.
+func (sh *shell) heredoc(l *scanner.Lexer) bool {
+	var h scanner.Heredoc
+	if l.UtilMatchHeredocStart(&h) {
+		sh.heredocs = append(sh.heredocs, h)
+		return true
+	}
+	return false
+}
+
+// assign matches the name of an assignment, like NAME=value.
+func (sh *shell) assign(l *scanner.Lexer) bool {
+	m := l.Scanner
+	if l.UtilMatchIdent(shellName) && l.EqualByte('=') {
+		l.Emit(NameVariable)
+		l.Next()
+		l.Emit(Operator)
+		return true
+	}
+	l.Back(m)
+	return false
+}
+
+// isShellSpecial tells if c is a special parameter, like $?.
+func isShellSpecial(c byte) bool {
+	return c >= '0' && c <= '9' || strings.IndexByte("?#@*!$-", c) >= 0
+}
+
+// #endregion Shell
+
+// #region SQL
+
+var sqlWords = newWords(true, map[scanner.Kind]string{
+	Keyword: `add all alter and as asc begin between by case check column
+		commit constraint create cross default delete desc distinct drop
+		else end except exists foreign from full group having if in index
+		inner insert intersect into is join key left like limit not offset
+		on or order outer primary references returning right rollback
+		select set table then transaction union unique update using values
+		view when where with`,
+	KeywordConstant: "null true false",
+	KeywordType: `bigint binary blob boolean char date datetime decimal
+		double float int integer interval json numeric real serial
+		smallint text time timestamp uuid varchar`,
+	NameBuiltin: "avg coalesce count lower max min now sum upper",
+})
+
+var sqlOps = scanner.NewLiterals(
+	"=", "<>", "!=", "<", "<=", ">", ">=", "+", "-", "*", "/", "%", "||", "::",
+)
+
+// sqlNumber is an SQL number, like 1, 1.5, .5 and 1e3.
+var sqlNumber = scanner.NumberFormat{Float: true, BareDot: true, LeadingZeros: true}
+
+func lexSQL(l *scanner.Lexer) scanner.StateFn {
+	l.WS()
+	l.Ignore()
+	switch {
+	case !l.More():
+		return nil
+	case matchComment(&l.Scanner, &scanner.TriviaSQL):
+		l.Emit(Comment)
+	case l.EqualByte('\''):
+		// Quotes are escaped by doubling them, like 'it''s'.
+		for l.MatchByte('\'') && matchUntil(&l.Scanner, "'") && l.EqualByte('\'') {
+		}
+		l.Emit(String)
+	case l.EqualByte('"') || l.EqualByte('`'):
+		q := string(l.Curr())
+		l.Next()
+		matchUntil(&l.Scanner, q)
+		l.Emit(Name)
+	case l.UtilMatchNumberFormat(sqlNumber):
+		l.Emit(Number)
+	case l.MatchByte('?'):
+		l.Emit(NameVariable)
+	case (l.EqualByte(':') || l.EqualByte('@') || l.EqualByte('$')) && matchParam(&l.Scanner):
+		l.Emit(NameVariable)
+	case sqlWords.match(l, scanner.IdentSQL):
+	case l.UtilMatchIdent(scanner.IdentSQL):
+		l.Emit(Name)
+	case matchOp(&l.Scanner, sqlOps):
+		l.Emit(Operator)
+	case l.MatchByte('(') || l.MatchByte(')') || l.MatchByte(',') || l.MatchByte(';') || l.MatchByte('.'):
+		l.Emit(Punct)
+	default:
+		l.NextRune()
+	}
+	return lexSQL
+}
+
+// matchParam matches a parameter, like :name, @name or $1.
+func matchParam(s *scanner.Scanner) bool {
+	ss := *s
+	ss.Next()
+	if ss.UtilMatchIdent(scanner.IdentSQL) || ss.UtilMatchInteger() {
+		*s = ss
+		return true
+	}
+	return false
+}
+
+// #endregion SQL
+
+// #region Helpers
+
+// words maps keywords to kinds.
+type words struct {
+	set   *scanner.Literals
+	kinds []scanner.Kind
+}
+
+// newWords returns the words of each kind,
+// given as a string of whitespace separated words.
+// The kinds go in order, so a word in two groups
+// always gets the same kind.
+func newWords(fold bool, groups map[scanner.Kind]string) *words {
+	var w words
+	var lits []string
+	kinds := make([]scanner.Kind, 0, len(groups))
+	for k := range groups {
+		kinds = append(kinds, k)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i] < kinds[j] })
+	for _, k := range kinds {
+		for _, v := range strings.Fields(groups[k]) {
+			lits = append(lits, v)
+			w.kinds = append(w.kinds, k)
+		}
+	}
+	if fold {
+		w.set = scanner.NewLiteralsFold(lits...)
+	} else {
+		w.set = scanner.NewLiterals(lits...)
+	}
+	return &w
+}
+
+// match matches and emits a keyword.
+func (w *words) match(l *scanner.Lexer, ident *scanner.Ident) bool {
+	id, ok := l.MatchKeyword(w.set, ident)
+	if ok {
+		l.Emit(w.kinds[id])
+	}
+	return ok
+}
+
+// matchComment matches a line or block comment of t.
+// An unclosed block comment goes to the end.
+func matchComment(s *scanner.Scanner, t *scanner.Trivia) bool {
+	for _, v := range t.Line {
+		if s.Match(v) {
+			if !s.MatchUntilByte('\n') {
+				s.Advance(len(*s))
+			}
+			return true
+		}
+	}
+	for _, p := range t.Block {
+		if s.Match(p.Open) {
+			matchUntil(s, p.Close)
+			return true
+		}
+	}
+	return false
+}
+
+// matchQuoted matches a string quoted by q with an escape.
+// An unclosed string goes to the end of the line.
+func matchQuoted(s *scanner.Scanner, q, esc string) {
+	s.Match(q)
+	line := *s
+	if n := strings.IndexByte(line.String(), '\n'); n >= 0 {
+		line = line[:n]
+	}
+	// An escape at the end of the line escapes nothing.
+	end := line
+	for end.More() && !end.Match(q) {
+		end.Match(esc)
+		end.Next()
+	}
+	s.Advance(len(line) - len(end))
+}
+
+// matchUntil matches until and including v,
+// or to the end if v is missing. It returns
+// false in that case.
+func matchUntil(s *scanner.Scanner, v string) bool {
+	if s.MatchUntil(v) {
+		s.Advance(len(v))
+		return true
+	}
+	s.Advance(len(*s))
+	return false
+}
+
+// matchOp matches the longest operator of a set.
+func matchOp(s *scanner.Scanner, set *scanner.Literals) bool {
+	_, ok := s.MatchLongest(set)
+	return ok
+}
+
+// #endregion Helpers
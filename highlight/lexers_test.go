@@ -0,0 +1,151 @@
+package highlight
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ofabricio/scanner"
+)
+
+// styled returns the styled tokens of src, like "k:if n:x".
+func styled(lang *Lang, src string) string {
+	var out []string
+	h := Highlighter{Lang: lang}
+	h.render(src, func(text, class string) {
+		if class != "" {
+			out = append(out, class+":"+text)
+		}
+	})
+	return strings.Join(out, " ")
+}
+
+// #region Lexers
+
+func TestLexJSON(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+	}{
+		{give: `{"a" : [1, -2.5e3, true, null]}`, exp: `p:{ nt:"a" p:: p:[ m:1 p:, m:-2.5e3 p:, kc:true p:, kc:null p:] p:}`},
+		{give: `["x\"y", "z"]`, exp: `p:[ s:"x\"y" p:, s:"z" p:]`},
+		{give: `{"a": "open` + "\n}", exp: `p:{ nt:"a" p:: s:"open p:}`},
+		{give: `[truex, @]`, exp: `p:[ p:, p:]`},
+		{give: `["\`, exp: `p:[ s:"\`},
+	}
+	for _, tc := range tt {
+		assertEqual(t, tc.exp, styled(JSON, tc.give), tc.give)
+	}
+}
+
+func TestLexGo(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+	}{
+		{give: `func f() int { return 0x1F }`, exp: `k:func n:f p:( p:) kt:int p:{ k:return m:0x1F p:}`},
+		{give: "x := `a\\` + \"b\\\\\" + '\\''", exp: "n:x o::= s:`a\\` o:+ s:\"b\\\\\" o:+ s:'\\''"},
+		{give: "a // c\n/** d **/ b", exp: "n:a c:// c c:/** d **/ n:b"},
+		{give: `len(nil) &^= iffy`, exp: `nb:len p:( kc:nil p:) o:&^= n:iffy`},
+		{give: `a...`, exp: `n:a o:...`},
+		{give: `"open`, exp: `s:"open`},
+		{give: "s := \"C:\\\nx", exp: "n:s o::= s:\"C:\\ n:x"},
+		{give: `'\`, exp: `s:'\`},
+	}
+	for _, tc := range tt {
+		assertEqual(t, tc.exp, styled(Go, tc.give), tc.give)
+	}
+}
+
+func TestLexShell(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+	}{
+		{give: `echo "$HOME $(ls)" 'x' # c`, exp: `nb:echo s:"$HOME $(ls)" s:'x' c:# c`},
+		{give: `FOO=bar a#b $? ${A:-b} $x2`, exp: `nv:FOO o:= nv:$? nv:${A:-b} nv:$x2`},
+		{give: `if [ -f x.sh ]; then echo.sh; fi`, exp: `k:if o:; k:then o:; k:fi`},
+		{give: "cat <<EOF | wc\nbody\nEOF\necho", exp: "o:<<EOF o:| s:body\nEOF\n nb:echo"},
+		{give: "cat <<-A <<'B'\n\tx\n\tA\ny\nB\n", exp: "o:<<-A o:<<'B' s:\tx\n\tA\n s:y\nB\n"},
+		{give: "cat <<EOF\nopen", exp: "o:<<EOF s:open"},
+		{give: `a \"b && c >> d`, exp: `o:&& o:>>`},
+	}
+	for _, tc := range tt {
+		assertEqual(t, tc.exp, styled(Shell, tc.give), tc.give)
+	}
+}
+
+func TestLexSQL(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+	}{
+		{give: `SELECT a, COUNT(*) FROM "t"`, exp: `k:SELECT n:a p:, nb:COUNT p:( o:* p:) k:FROM n:"t"`},
+		{give: `where b = 'it''s' and c >= .5`, exp: `k:where n:b o:= s:'it''s' k:and n:c o:>= m:.5`},
+		{give: "x -- c\n/* d */ LIMIT :n OFFSET ?", exp: "n:x c:-- c c:/* d */ k:LIMIT nv::n k:OFFSET nv:?"},
+		{give: `CAST(x AS VARCHAR) IS NULL`, exp: `n:CAST p:( n:x k:AS kt:VARCHAR p:) k:IS kc:NULL`},
+		{give: `selected 'open`, exp: `n:selected s:'open`},
+	}
+	for _, tc := range tt {
+		assertEqual(t, tc.exp, styled(SQL, tc.give), tc.give)
+	}
+}
+
+func TestLexersCoverInput(t *testing.T) {
+	// Tokens are in order and inside the input.
+	srcs := []string{`{"a": [1, "b`, "func f() { `x", "cat <<EOF\n$(", "SELECT 'x"}
+	for _, lang := range Langs {
+		for _, src := range srcs {
+			pos := 0
+			l := scanner.NewLexer(src, lang.State)
+			for tok := l.NextToken(); tok.Kind != scanner.KindEOF; tok = l.NextToken() {
+				assertEqual(t, true, tok.Span.Start >= pos && tok.Span.End <= len(src), lang.Name, src, tok)
+				assertEqual(t, tok.Text, tok.Span.Text(src), lang.Name, src, tok)
+				pos = tok.Span.End
+			}
+		}
+	}
+}
+
+func TestLexersMalformed(t *testing.T) {
+	// Every prefix of a snippet is highlighted
+	// with no panic and with no text lost.
+	srcs := map[*Lang][]string{
+		JSON:  {`{"a\"b": ["c\`, `"\`, `{"a": tru, -, 1e, "\u12`},
+		Go:    {"s := \"C:\\\n", `'\`, "x := `raw /* c\n", `f(0x, 1e+, "a\"`},
+		Shell: {`echo "a\`, `x='b\`, "cat <<'E\n${a:-", `$(( 1 + "\`},
+		SQL:   {`SELECT 'a''`, `"b\`, "/* c\n-- d\n", `x = .e :`},
+	}
+	for _, lang := range Langs {
+		for _, src := range srcs[lang] {
+			for i := 0; i <= len(src); i++ {
+				var b strings.Builder
+				h := Highlighter{Lang: lang}
+				h.render(src[:i], func(text, class string) {
+					b.WriteString(text)
+				})
+				assertEqual(t, src[:i], b.String(), lang.Name)
+			}
+		}
+	}
+}
+
+func TestNewWords(t *testing.T) {
+	// A word in two groups gets the first kind.
+	for i := 0; i < 20; i++ {
+		w := newWords(false, map[scanner.Kind]string{KeywordType: "int", NameBuiltin: "int len", Keyword: "if int"})
+		l := scanner.NewLexer("int", nil)
+		assertEqual(t, true, w.match(l, scanner.IdentGo))
+		assertEqual(t, Keyword, l.NextToken().Kind)
+	}
+}
+
+func BenchmarkLexGo(b *testing.B) {
+	src := "package main\n\nfunc main() {\n\tx := []int{1, 2, 3} // c\n\tprintln(len(x), \"s\")\n}\n"
+	for i := 0; i < b.N; i++ {
+		l := scanner.NewLexer(src, lexGo)
+		for l.NextToken().Kind != scanner.KindEOF {
+		}
+	}
+}
+
+// #endregion Lexers
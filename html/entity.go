@@ -0,0 +1,179 @@
+package html
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// #region Entity
+
+// entities are the common named character references.
+// The ones of Latin-1 also work without ';', like &copy.
+// References not in it are left as they are.
+var entities = map[string]rune{
+	"amp": '&', "lt": '<', "gt": '>', "quot": '"', "apos": '\'',
+	"AMP": '&', "LT": '<', "GT": '>', "QUOT": '"', "COPY": '©', "REG": '®',
+	"OElig": 'Œ', "oelig": 'œ', "Scaron": 'Š', "scaron": 'š', "Yuml": 'Ÿ',
+	"fnof": 'ƒ', "circ": 'ˆ', "tilde": '˜',
+	"ensp": ' ', "emsp": ' ', "thinsp": ' ',
+	"zwnj": '‌', "zwj": '‍', "lrm": '‎', "rlm": '‏',
+	"ndash": '–', "mdash": '—', "lsquo": '‘', "rsquo": '’', "sbquo": '‚',
+	"ldquo": '“', "rdquo": '”', "bdquo": '„', "dagger": '†', "Dagger": '‡',
+	"bull": '•', "hellip": '…', "permil": '‰', "prime": '′', "Prime": '″',
+	"lsaquo": '‹', "rsaquo": '›', "oline": '‾', "frasl": '⁄', "euro": '€',
+	"trade": '™', "larr": '←', "uarr": '↑', "rarr": '→', "darr": '↓', "harr": '↔',
+	"forall": '∀', "part": '∂', "exist": '∃', "empty": '∅', "nabla": '∇',
+	"isin": '∈', "notin": '∉', "ni": '∋', "prod": '∏', "sum": '∑', "minus": '−',
+	"lowast": '∗', "radic": '√', "prop": '∝', "infin": '∞', "ang": '∠',
+	"and": '∧', "or": '∨', "cap": '∩', "cup": '∪', "int": '∫', "there4": '∴',
+	"sim": '∼', "cong": '≅', "asymp": '≈', "ne": '≠', "equiv": '≡', "le": '≤', "ge": '≥',
+	"sub": '⊂', "sup": '⊃', "nsub": '⊄', "sube": '⊆', "supe": '⊇',
+	"oplus": '⊕', "otimes": '⊗', "perp": '⊥', "sdot": '⋅',
+	"lceil": '⌈', "rceil": '⌉', "lfloor": '⌊', "rfloor": '⌋', "loz": '◊',
+	"spades": '♠', "clubs": '♣', "hearts": '♥', "diams": '♦',
+}
+
+// latin1 are the names of the characters 160 to 255.
+const latin1 = "nbsp iexcl cent pound curren yen brvbar sect uml copy ordf laquo not shy reg macr " +
+	"deg plusmn sup2 sup3 acute micro para middot cedil sup1 ordm raquo frac14 frac12 frac34 iquest " +
+	"Agrave Aacute Acirc Atilde Auml Aring AElig Ccedil Egrave Eacute Ecirc Euml Igrave Iacute Icirc Iuml " +
+	"ETH Ntilde Ograve Oacute Ocirc Otilde Ouml times Oslash Ugrave Uacute Ucirc Uuml Yacute THORN szlig " +
+	"agrave aacute acirc atilde auml aring aelig ccedil egrave eacute ecirc euml igrave iacute icirc iuml " +
+	"eth ntilde ograve oacute ocirc otilde ouml divide oslash ugrave uacute ucirc uuml yacute thorn yuml"
+
+// greek are the names of the Greek letters, upper
+// case from 913 and lower case from 945.
+const greek = "alpha beta gamma delta epsilon zeta eta theta iota kappa lambda mu nu xi omicron pi rho sigmaf sigma tau upsilon phi chi psi omega"
+
+func init() {
+	for i, name := range strings.Fields(latin1) {
+		entities[name] = rune(160 + i)
+	}
+	for i, name := range strings.Fields(greek) {
+		if name != "sigmaf" {
+			entities[strings.ToUpper(name[:1])+name[1:]] = rune(913 + i)
+		}
+		entities[name] = rune(945 + i)
+	}
+	entities["thetasym"], entities["upsih"], entities["piv"] = 'ϑ', 'ϒ', 'ϖ'
+}
+
+// win1252 are the characters that references to
+// 0x80 to 0x9F mean, as in Windows-1252.
+var win1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
+
+// decode decodes the references of s and
+// normalizes its line breaks to '\n'.
+func decode(s string, attr bool) string {
+	if strings.IndexByte(s, '&') < 0 && strings.IndexByte(s, '\r') < 0 {
+		return s
+	}
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '&':
+			var n int
+			if b, n = reference(b, s[i:], attr); n > 0 {
+				i += n
+				continue
+			}
+			b = append(b, c)
+			i++
+		case c == '\r':
+			if i++; i < len(s) && s[i] == '\n' {
+				i++
+			}
+			b = append(b, '\n')
+		default:
+			b = append(b, c)
+			i++
+		}
+	}
+	return string(b)
+}
+
+// reference decodes the reference at the start of s into
+// b. It returns its length, or zero if there is none. In
+// attributes, a reference without ';' followed by an
+// alphanumeric or '=' is not one, like in ?a=1&copy=2.
+func reference(b []byte, s string, attr bool) ([]byte, int) {
+	if strings.HasPrefix(s, "&#") {
+		return number(b, s)
+	}
+	n := 1
+	for n < len(s) && isAlnum(s[n]) {
+		n++
+	}
+	name := s[1:n]
+	if r, ok := entities[name]; ok && n < len(s) && s[n] == ';' {
+		return utf8.AppendRune(b, r), n + 1
+	}
+	// The longest legacy name without ';'.
+	for k := len(name); k > 0; k-- {
+		r, ok := entities[name[:k]]
+		if !ok || r > 0xFF || name[:k] == "apos" {
+			continue
+		}
+		if attr && (k < len(name) || n < len(s) && s[n] == '=') {
+			return b, 0
+		}
+		return utf8.AppendRune(b, r), k + 1
+	}
+	return b, 0
+}
+
+// number decodes a numeric reference, like &#65; or
+// &#x41;. The ';' is optional. Invalid characters are
+// U+FFFD and 0x80 to 0x9F are as in Windows-1252.
+func number(b []byte, s string) ([]byte, int) {
+	i, base := 2, rune(10)
+	if len(s) > 2 && (s[2] == 'x' || s[2] == 'X') {
+		i, base = 3, 16
+	}
+	ini := i
+	var r rune
+	for ; i < len(s); i++ {
+		d := digit(s[i])
+		if d >= base {
+			break
+		}
+		if r <= utf8.MaxRune {
+			r = r*base + d
+		}
+	}
+	if i == ini {
+		return b, 0
+	}
+	if i < len(s) && s[i] == ';' {
+		i++
+	}
+	switch {
+	case r >= 0x80 && r <= 0x9F:
+		r = win1252[r-0x80]
+	case r == 0 || !utf8.ValidRune(r):
+		r = utf8.RuneError
+	}
+	return utf8.AppendRune(b, r), i
+}
+
+// digit returns the value of a hex digit, or 16.
+func digit(c byte) rune {
+	switch {
+	case c >= '0' && c <= '9':
+		return rune(c - '0')
+	case c >= 'a' && c <= 'f':
+		return rune(c-'a') + 10
+	case c >= 'A' && c <= 'F':
+		return rune(c-'A') + 10
+	}
+	return 16
+}
+
+func isAlnum(c byte) bool {
+	return isLetter(c) || c >= '0' && c <= '9'
+}
+
+// #endregion Entity
@@ -0,0 +1,55 @@
+package html
+
+import (
+	"testing"
+)
+
+// #region Entity
+
+func TestDecode(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+	}{
+		{give: "a &amp; b &lt;&gt; &quot;&apos;", exp: `a & b <> "'`},
+		{give: "&copy; &copy 2 &COPY;", exp: "© © 2 ©"},
+		{give: "&hellip;&mdash;&euro;&hearts;", exp: "…—€♥"},
+		{give: "&Omega;&omega;&sigmaf;&Sigma;", exp: "ΩωςΣ"},
+		{give: "&notit; &notin;", exp: "¬it; ∉"},
+		{give: "&hellip &bogus; & &;", exp: "&hellip &bogus; & &;"},
+		{give: "&apos", exp: "&apos"},
+		{give: "&#65;&#x42;&#X43;&#68", exp: "ABCD"},
+		{give: "&#0;&#xD800;&#x110000;&#99999999999;", exp: "����"},
+		{give: "&#x80;&#x99;&#x81;", exp: "€™\u0081"},
+		{give: "&#;&#x;", exp: "&#;&#x;"},
+		{give: "a\r\nb\rc", exp: "a\nb\nc"},
+	}
+	for _, tc := range tt {
+		assertEqual(t, tc.exp, decode(tc.give, false), tc.give)
+	}
+}
+
+func TestDecodeAttr(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+	}{
+		{give: "?a=1&copy=2", exp: "?a=1&copy=2"},
+		{give: "?a=1&copyx", exp: "?a=1&copyx"},
+		{give: "?a=1&copy;x", exp: "?a=1©x"},
+		{give: "a&amp;b", exp: "a&b"},
+		{give: "a&amp b", exp: "a& b"},
+	}
+	for _, tc := range tt {
+		assertEqual(t, tc.exp, decode(tc.give, true), tc.give)
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	src := "Tom &amp; Jerry &lt;3 &copy 2024 &#x1F600; &hellip; &notin; plain text"
+	for i := 0; i < b.N; i++ {
+		decode(src, false)
+	}
+}
+
+// #endregion Entity
@@ -0,0 +1,352 @@
+// Package html tokenizes HTML5 with the scanner the
+// forgiving way browsers do: it never fails. Malformed
+// markup becomes text, comments or is dropped, like the
+// HTML5 tokenizer does. It also has an allow-list
+// sanitizer built on the tokenizer.
+package html
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ofabricio/scanner"
+)
+
+// #region Token
+
+// Kind is the kind of a Token.
+type Kind int
+
+const (
+	StartTag Kind = iota + 1
+	EndTag
+	Text
+	Comment
+	Doctype
+)
+
+func (k Kind) String() string {
+	switch k {
+	case StartTag:
+		return "StartTag"
+	case EndTag:
+		return "EndTag"
+	case Text:
+		return "Text"
+	case Comment:
+		return "Comment"
+	case Doctype:
+		return "Doctype"
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Attr is an attribute of a start tag.
+type Attr struct {
+	Name  string // Lower case.
+	Value string // With references decoded.
+	Span  scanner.Span
+}
+
+// Token is a token of a document.
+type Token struct {
+	Kind Kind
+	// Name is the lower case name of a tag.
+	Name string
+	// Attrs are the attributes of a start tag, without
+	// duplicates. The slice is reused by the next call.
+	Attrs []Attr
+	// Data is the decoded text of a Text, the text of
+	// a Comment and the declaration of a Doctype.
+	Data string
+	// SelfClosing tells if a start tag is like <br/>.
+	SelfClosing bool
+	Span        scanner.Span
+}
+
+// Attr returns the value of an attribute.
+func (t Token) Attr(name string) (string, bool) {
+	for _, a := range t.Attrs {
+		if a.Name == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// #endregion Token
+
+// #region Tokenizer
+
+// Tokenizer reads the tokens of an HTML document.
+type Tokenizer struct {
+	src, s scanner.Scanner
+	// end is the end tag of the raw text
+	// element whose text comes next.
+	end   string
+	attrs []Attr
+}
+
+// NewTokenizer returns a tokenizer of src.
+func NewTokenizer(src string) *Tokenizer {
+	s := scanner.Scanner(src)
+	return &Tokenizer{src: s, s: s}
+}
+
+// rawText are the elements whose text has no tags, up to
+// their end tag. rcdata are the ones with references.
+var (
+	rawText = map[string]string{
+		"script": "</script", "style": "</style", "xmp": "</xmp", "iframe": "</iframe",
+		"noembed": "</noembed", "noframes": "</noframes", "title": "</title", "textarea": "</textarea",
+	}
+	rcdata = map[string]bool{"title": true, "textarea": true}
+)
+
+// voids are the elements with no end tag.
+var voids = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// IsVoid tells if an element has no end tag, like br.
+func IsVoid(name string) bool {
+	return voids[name]
+}
+
+// Next returns the next token, or false at the end.
+func (t *Tokenizer) Next() (Token, bool) {
+	if t.end != "" {
+		if tok := t.rawText(); tok.Data != "" {
+			return tok, true
+		}
+	}
+	for t.s.More() {
+		at := t.s
+		switch {
+		case t.s.Equal("<!--"):
+			return t.comment(), true
+		case t.s.Equal("</>"):
+			t.s.Advance(3)
+		case t.s.Equal("</") && len(t.s) > 2 && isLetter(t.s[2]):
+			return t.tag()
+		case t.s.Equal("</"), t.s.Equal("<!") && !isDoctype(t.s):
+			t.s.Advance(2)
+			return t.bogus(at), true
+		case t.s.Equal("<?"):
+			t.s.Next()
+			return t.bogus(at), true
+		case t.s.Equal("<!"):
+			return t.doctype(), true
+		case t.s.EqualByte('<') && len(t.s) > 1 && isLetter(t.s[1]):
+			return t.tag()
+		default:
+			return t.text(), true
+		}
+	}
+	return Token{}, false
+}
+
+// tag reads a start or an end tag. A tag
+// cut by the end of the input is dropped.
+func (t *Tokenizer) tag() (Token, bool) {
+	at := t.s
+	tok := Token{Kind: StartTag}
+	if t.s.Next(); t.s.MatchByte('/') {
+		tok.Kind = EndTag
+	}
+	m := t.s
+	t.s.MatchWhileByteBy(func(c byte) bool { return !isSpace(c) && c != '/' && c != '>' })
+	tok.Name = lower(t.s.Token(m))
+	t.attrs = t.attrs[:0]
+	for {
+		t.s.MatchWhileByteBy(isSpace)
+		switch {
+		case !t.s.More():
+			return Token{}, false
+		case t.s.Match("/>"):
+			tok.SelfClosing = true
+		case t.s.MatchByte('/'):
+			continue
+		case !t.s.MatchByte('>'):
+			t.attr()
+			continue
+		}
+		break
+	}
+	tok.Span = t.src.Span(at, t.s)
+	if tok.Kind == StartTag {
+		tok.Attrs = t.attrs
+		t.end = rawText[tok.Name]
+	}
+	return tok, true
+}
+
+// attr reads an attribute. Its value can be
+// quoted, unquoted or missing, like <a b=c d>.
+func (t *Tokenizer) attr() {
+	at := t.s
+	// The name can start with '='.
+	t.s.Next()
+	t.s.MatchWhileByteBy(func(c byte) bool { return !isSpace(c) && c != '/' && c != '>' && c != '=' })
+	name := lower(t.s.Token(at))
+	var v string
+	end := t.s
+	t.s.MatchWhileByteBy(isSpace)
+	if t.s.MatchByte('=') {
+		t.s.MatchWhileByteBy(isSpace)
+		m := t.s
+		if q := t.s.Curr(); q == '"' || q == '\'' {
+			t.s.Next()
+			if !t.s.MatchUntilByte(q) {
+				t.s.Advance(len(t.s))
+				return
+			}
+			t.s.Next()
+			v = m[1 : len(m)-len(t.s)-1].String()
+		} else {
+			t.s.MatchWhileByteBy(func(c byte) bool { return !isSpace(c) && c != '>' })
+			v = t.s.Token(m)
+		}
+		v, end = decode(v, true), t.s
+	} else {
+		t.s = end
+	}
+	for _, a := range t.attrs {
+		if a.Name == name {
+			// The first one wins.
+			return
+		}
+	}
+	t.attrs = append(t.attrs, Attr{Name: name, Value: v, Span: t.src.Span(at, end)})
+}
+
+// rawText reads the text of a raw text element up to its
+// end tag, found with an ASCII case-insensitive match, as
+// browsers do. The text of title and textarea has
+// references. Others are as is.
+func (t *Tokenizer) rawText() Token {
+	at := t.s
+	for {
+		if !t.s.MatchUntil("</") {
+			t.s.Advance(len(t.s))
+			break
+		}
+		if hasPrefixLower(t.s.String(), t.end) {
+			if c := t.s[len(t.end):]; len(c) == 0 || isSpace(c[0]) || c[0] == '/' || c[0] == '>' {
+				break
+			}
+		}
+		t.s.Next()
+	}
+	v := t.s.Token(at)
+	if rcdata[strings.TrimPrefix(t.end, "</")] {
+		v = decode(v, false)
+	}
+	t.end = ""
+	return Token{Kind: Text, Data: v, Span: t.src.Span(at, t.s)}
+}
+
+// text reads text up to markup.
+func (t *Tokenizer) text() Token {
+	at := t.s
+	t.s.Next()
+	for t.s.MatchUntilByte('<') {
+		if len(t.s) > 1 && (isLetter(t.s[1]) || t.s[1] == '/' || t.s[1] == '!' || t.s[1] == '?') {
+			break
+		}
+		t.s.Next()
+	}
+	if !t.s.EqualByte('<') {
+		t.s.Advance(len(t.s))
+	}
+	return Token{Kind: Text, Data: decode(t.s.Token(at), false), Span: t.src.Span(at, t.s)}
+}
+
+// comment reads a comment. <!--> and <!---> are
+// empty ones. It ends at the end of the input too.
+func (t *Tokenizer) comment() Token {
+	at := t.s
+	t.s.Advance(4)
+	var v string
+	if !t.s.MatchByte('>') && !t.s.Match("->") {
+		m := t.s
+		if !t.s.MatchUntil("-->") {
+			t.s.Advance(len(t.s))
+		}
+		v = t.s.Token(m)
+		t.s.Match("-->")
+	}
+	return Token{Kind: Comment, Data: v, Span: t.src.Span(at, t.s)}
+}
+
+// bogus reads a bogus comment, like <?php ?> or
+// </ x>, whose text is up to the next '>'.
+func (t *Tokenizer) bogus(at scanner.Scanner) Token {
+	m := t.s
+	if !t.s.MatchUntilByte('>') {
+		t.s.Advance(len(t.s))
+	}
+	v := t.s.Token(m)
+	t.s.MatchByte('>')
+	return Token{Kind: Comment, Data: v, Span: t.src.Span(at, t.s)}
+}
+
+// doctype reads a DOCTYPE, like <!DOCTYPE html>.
+func (t *Tokenizer) doctype() Token {
+	at := t.s
+	t.s.Advance(len("<!doctype"))
+	tok := t.bogus(at)
+	tok.Kind, tok.Data = Doctype, strings.TrimSpace(tok.Data)
+	return tok
+}
+
+func isDoctype(s scanner.Scanner) bool {
+	s.Advance(2)
+	return s.MatchFold("doctype")
+}
+
+// lower returns s in ASCII lower case,
+// which allocates only if s has upper case.
+func lower(s string) string {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c >= 'A' && c <= 'Z' {
+			b := []byte(s)
+			for j := i; j < len(b); j++ {
+				if c := b[j]; c >= 'A' && c <= 'Z' {
+					b[j] = c + 'a' - 'A'
+				}
+			}
+			return string(b)
+		}
+	}
+	return s
+}
+
+// hasPrefixLower tells if s starts with the lower
+// case prefix, ignoring ASCII case only.
+func hasPrefixLower(s, prefix string) bool {
+	if len(s) < len(prefix) {
+		return false
+	}
+	for i := 0; i < len(prefix); i++ {
+		c := s[i]
+		if c >= 'A' && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		if c != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\f' || c == '\r'
+}
+
+// #endregion Tokenizer
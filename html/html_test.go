@@ -0,0 +1,164 @@
+package html
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// #region Tokenizer
+
+func TestTokenizer(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  []string
+	}{
+		{
+			give: `<!DOCTYPE html><P Class=x id="y" data-a='b' checked>a</p>`,
+			exp:  []string{`Doctype "html"`, `StartTag p [class=x id=y data-a=b checked=]`, `Text "a"`, `EndTag p`},
+		},
+		{
+			give: `<br/><img src=a.png alt="a b"><input disabled/>`,
+			exp:  []string{`StartTag br [] />`, `StartTag img [src=a.png alt=a b]`, `StartTag input [disabled=] />`},
+		},
+		{
+			give: `<a b = "1" b="2" / c=3>`,
+			exp:  []string{`StartTag a [b=1 c=3]`},
+		},
+		{
+			give: `<a b="x>y" c='"' d=e"f =g>`,
+			exp:  []string{`StartTag a [b=x>y c=" d=e"f =g=]`},
+		},
+		{
+			give: "<script>if (a<b && c) { x = '</p>' }</script>",
+			exp:  []string{`StartTag script []`, `Text "if (a<b && c) { x = '</p>' }"`, `EndTag script`},
+		},
+		{
+			give: "<ScRiPt>a</scriptx></SCRIPT ><style>p>b{}</style",
+			exp:  []string{`StartTag script []`, `Text "a</scriptx>"`, `EndTag script`, `StartTag style []`, `Text "p>b{}"`},
+		},
+		{
+			// Only ASCII letters fold: U+017F is not an 's'.
+			give: "<script>a</ſcript>b</SCRIPT>",
+			exp:  []string{`StartTag script []`, "Text \"a</ſcript>b\"", `EndTag script`},
+		},
+		{
+			give: "<title>&lt;b&gt;</title><script></script>",
+			exp:  []string{`StartTag title []`, `Text "<b>"`, `EndTag title`, `StartTag script []`, `EndTag script`},
+		},
+		{
+			give: "<!-- a --><!----><!--><!---><!-- b",
+			exp:  []string{`Comment " a "`, `Comment ""`, `Comment ""`, `Comment ""`, `Comment " b"`},
+		},
+		{
+			give: "<?php x ?></ x><!ELEMENT a></>",
+			exp:  []string{`Comment "?php x ?"`, `Comment " x"`, `Comment "ELEMENT a"`},
+		},
+		{
+			give: "1 < 2 <3 a&b <",
+			exp:  []string{`Text "1 < 2 <3 a&b <"`},
+		},
+		{
+			give: "a<b c='d",
+			exp:  []string{`Text "a"`},
+		},
+		{
+			give: "</A x=1>",
+			exp:  []string{`EndTag a`},
+		},
+		{
+			give: "",
+			exp:  nil,
+		},
+	}
+	for _, tc := range tt {
+		assertEqual(t, tc.exp, tokens(tc.give), tc.give)
+	}
+}
+
+func TestTokenizerSpan(t *testing.T) {
+	src := `<a href=x title="t">b</a><!--c-->`
+	z := NewTokenizer(src)
+	var got []string
+	for {
+		tok, ok := z.Next()
+		if !ok {
+			break
+		}
+		got = append(got, tok.Span.Text(src))
+		for _, a := range tok.Attrs {
+			got = append(got, a.Span.Text(src))
+		}
+	}
+	assertEqual(t, []string{`<a href=x title="t">`, `href=x`, `title="t"`, `b`, `</a>`, `<!--c-->`}, got)
+}
+
+func TestTokenAttr(t *testing.T) {
+	z := NewTokenizer(`<a HREF="x" b>`)
+	tok, _ := z.Next()
+	v, ok := tok.Attr("href")
+	assertEqual(t, "x", v)
+	assertEqual(t, true, ok)
+	_, ok = tok.Attr("c")
+	assertEqual(t, false, ok)
+}
+
+func TestIsVoid(t *testing.T) {
+	assertEqual(t, true, IsVoid("br"))
+	assertEqual(t, true, IsVoid("img"))
+	assertEqual(t, false, IsVoid("p"))
+}
+
+func BenchmarkTokenizer(b *testing.B) {
+	src := strings.Repeat(`<div class=item><a href="/p?id=1&amp;x=2">Item &copy; 2024</a><br><script>if (a < b) {}</script></div>`, 10)
+	b.SetBytes(int64(len(src)))
+	for i := 0; i < b.N; i++ {
+		z := NewTokenizer(src)
+		for {
+			if _, ok := z.Next(); !ok {
+				break
+			}
+		}
+	}
+}
+
+// #endregion Tokenizer
+
+// tokens prints the tokens of src.
+func tokens(src string) []string {
+	var out []string
+	z := NewTokenizer(src)
+	for {
+		tok, ok := z.Next()
+		if !ok {
+			return out
+		}
+		s := tok.Kind.String()
+		switch tok.Kind {
+		case StartTag:
+			var attrs []string
+			for _, a := range tok.Attrs {
+				attrs = append(attrs, a.Name+"="+a.Value)
+			}
+			s += " " + tok.Name + " [" + strings.Join(attrs, " ") + "]"
+			if tok.SelfClosing {
+				s += " />"
+			}
+		case EndTag:
+			s += " " + tok.Name
+		default:
+			s += " " + fmt.Sprintf("%q", tok.Data)
+		}
+		out = append(out, s)
+	}
+}
+
+func assertEqual(t *testing.T, exp, got any, msgs ...any) bool {
+	t.Helper()
+	if !reflect.DeepEqual(exp, got) {
+		t.Errorf("\nExp:\n%v\nGot:\n%v\nMsg: %v", exp, got, fmt.Sprint(msgs...))
+		return false
+	}
+	return true
+}
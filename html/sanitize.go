@@ -0,0 +1,132 @@
+package html
+
+import (
+	"strings"
+)
+
+// #region Sanitizer
+
+// Sanitizer rewrites HTML keeping only the allowed elements
+// and attributes. Other tags are dropped, but not their text,
+// unless they are raw text elements, like script. Comments
+// and DOCTYPEs are dropped. The output is balanced.
+type Sanitizer struct {
+	// Elements are the allowed elements
+	// and their allowed attributes.
+	Elements map[string][]string
+	// Schemes are the allowed schemes of URL attributes,
+	// like href. Relative URLs are always allowed.
+	Schemes []string
+}
+
+// Basic allows text formatting, links, images and lists.
+var Basic = &Sanitizer{
+	Elements: map[string][]string{
+		"p": nil, "br": nil, "hr": nil, "b": nil, "i": nil, "u": nil, "s": nil,
+		"em": nil, "strong": nil, "small": nil, "sub": nil, "sup": nil,
+		"code": nil, "pre": nil, "blockquote": {"cite"},
+		"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+		"ul": nil, "ol": nil, "li": nil,
+		"a": {"href", "title"}, "img": {"src", "alt", "title", "width", "height"},
+	},
+	Schemes: []string{"http", "https", "mailto"},
+}
+
+// urlAttrs are the attributes whose value is a URL.
+var urlAttrs = map[string]bool{
+	"href": true, "src": true, "cite": true, "action": true,
+	"formaction": true, "poster": true, "background": true, "longdesc": true,
+}
+
+// escaper escapes text and attribute values.
+var escaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+// Sanitize returns the sanitized src.
+func (s *Sanitizer) Sanitize(src string) string {
+	var b strings.Builder
+	var open []string
+	t := NewTokenizer(src)
+	for {
+		tok, ok := t.Next()
+		if !ok {
+			break
+		}
+		switch tok.Kind {
+		case Text:
+			escaper.WriteString(&b, tok.Data)
+		case StartTag:
+			allow, ok := s.Elements[tok.Name]
+			if !ok {
+				if t.end != "" {
+					// Drops the text of script and the like.
+					t.rawText()
+				}
+				continue
+			}
+			b.WriteString("<" + tok.Name)
+			for _, a := range tok.Attrs {
+				if !has(allow, a.Name) || urlAttrs[a.Name] && !s.allowedURL(a.Value) {
+					continue
+				}
+				b.WriteString(" " + a.Name + `="`)
+				escaper.WriteString(&b, a.Value)
+				b.WriteByte('"')
+			}
+			b.WriteByte('>')
+			if !IsVoid(tok.Name) {
+				open = append(open, tok.Name)
+			}
+		case EndTag:
+			// Closes the element and the ones inside it.
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == tok.Name {
+					closeAll(&b, open[i:])
+					open = open[:i]
+					break
+				}
+			}
+		}
+	}
+	closeAll(&b, open)
+	return b.String()
+}
+
+// allowedURL tells if a URL is relative or has an allowed
+// scheme. Browsers ignore whitespaces and control characters
+// in a scheme, like in "java\tscript:", so they are too.
+func (s *Sanitizer) allowedURL(v string) bool {
+	v = strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, v)
+	i := strings.IndexAny(v, ":/?#")
+	if i < 0 || v[i] != ':' {
+		return true
+	}
+	for _, scheme := range s.Schemes {
+		if strings.EqualFold(v[:i], scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// closeAll writes the end tags of open, innermost first.
+func closeAll(b *strings.Builder, open []string) {
+	for i := len(open) - 1; i >= 0; i-- {
+		b.WriteString("</" + open[i] + ">")
+	}
+}
+
+func has(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// #endregion Sanitizer
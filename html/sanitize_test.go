@@ -0,0 +1,55 @@
+package html
+
+import (
+	"strings"
+	"testing"
+)
+
+// #region Sanitizer
+
+func TestSanitize(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+	}{
+		{give: "<p>Hi <b>there</b></p>", exp: "<p>Hi <b>there</b></p>"},
+		{give: `<P ONCLICK="x()">a</P>`, exp: "<p>a</p>"},
+		{give: "<div><span>a</span></div>", exp: "a"},
+		{give: "a<script>alert('<b>')</script>b", exp: "ab"},
+		{give: "<style>p{}</style><SCRIPT>x</SCRIPT>", exp: ""},
+		{give: "<!-- c --><!DOCTYPE html>a", exp: "a"},
+		{give: "1 < 2 &amp; 3 > 2", exp: "1 &lt; 2 &amp; 3 &gt; 2"},
+		{give: `<a href="/x" title='"t"' target=_blank>a</a>`, exp: `<a href="/x" title="&quot;t&quot;">a</a>`},
+		{give: `<a href="https://a.b/?c=1&amp;d">a</a>`, exp: `<a href="https://a.b/?c=1&amp;d">a</a>`},
+		{give: `<a href="javascript:x()">a</a>`, exp: "<a>a</a>"},
+		{give: `<a href="JaVa&#9;Script:x()">a</a>`, exp: "<a>a</a>"},
+		{give: `<a href=" java script:x()">a</a>`, exp: "<a>a</a>"},
+		{give: `<img src=data:x onerror=y><br/>`, exp: "<img><br>"},
+		{give: `<a href="a:b/c">a</a><a href="a/b:c">b</a>`, exp: `<a>a</a><a href="a/b:c">b</a>`},
+		{give: "<b><i>a</b>c</i>", exp: "<b><i>a</i></b>c"},
+		{give: "<ul><li>a<li>b", exp: "<ul><li>a<li>b</li></li></ul>"},
+		{give: "</p>a", exp: "a"},
+		{give: "<b/>a", exp: "<b>a</b>"},
+	}
+	for _, tc := range tt {
+		assertEqual(t, tc.exp, Basic.Sanitize(tc.give), tc.give)
+	}
+}
+
+func TestSanitizeCustom(t *testing.T) {
+	s := &Sanitizer{
+		Elements: map[string][]string{"a": {"href"}, "script": nil},
+		Schemes:  []string{"ftp"},
+	}
+	assertEqual(t, `<a href="ftp://x">a</a><a>b</a>c`, s.Sanitize(`<a href="ftp://x">a</a><a href="http://x">b</a><b>c</b>`))
+	assertEqual(t, "<script>a &lt; b</script>", s.Sanitize("<script>a < b</script>"))
+}
+
+func BenchmarkSanitize(b *testing.B) {
+	src := strings.Repeat(`<div class=x><p onclick="y">Hi <b>there</b> <a href="https://a.b/?c=1&amp;d" target=_blank>link</a><script>alert(1)</script></p></div>`, 10)
+	for i := 0; i < b.N; i++ {
+		Basic.Sanitize(src)
+	}
+}
+
+// #endregion Sanitizer
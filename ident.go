@@ -0,0 +1,162 @@
+package scanner
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// #region Ident
+
+// Ident describes an identifier by its
+// start and continue characters.
+type Ident struct {
+	Start    func(rune) bool
+	Continue func(rune) bool
+	// ASCII lookup tables, so pure
+	// ASCII input is not decoded.
+	start, cont [2]uint64
+}
+
+// NewIdent returns an identifier given
+// its start and continue functions.
+func NewIdent(start, cont func(rune) bool) *Ident {
+	id := &Ident{Start: start, Continue: cont}
+	for c := 0; c < utf8.RuneSelf; c++ {
+		if start(rune(c)) {
+			id.start[c/64] |= 1 << (c % 64)
+		}
+		if cont(rune(c)) {
+			id.cont[c/64] |= 1 << (c % 64)
+		}
+	}
+	return id
+}
+
+// ascii tells if the ASCII c starts or continues an
+// identifier. An Ident literal has no tables, so its
+// functions are used.
+func (id *Ident) ascii(c byte, start bool) bool {
+	set, f := &id.cont, id.Continue
+	if start {
+		set, f = &id.start, id.Start
+	}
+	if id.start == [2]uint64{} && id.cont == [2]uint64{} {
+		return f(rune(c))
+	}
+	return set[c/64]&(1<<(c%64)) != 0
+}
+
+// Identifiers of some languages.
+// Escapes like JavaScript's \u0041 are not matched.
+var (
+	// IdentUAX31 is the default identifier of UAX #31.
+	IdentUAX31 = NewIdent(IsXIDStart, IsXIDContinue)
+	// IdentGo is a letter or '_' followed by letters, '_' or digits.
+	IdentGo = NewIdent(
+		func(r rune) bool { return r == '_' || unicode.IsLetter(r) },
+		func(r rune) bool { return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) },
+	)
+	// IdentJS is ID_Start, '$' or '_' followed
+	// by ID_Continue, '$', ZWNJ or ZWJ.
+	IdentJS = NewIdent(
+		func(r rune) bool { return r == '$' || r == '_' || IsIDStart(r) },
+		func(r rune) bool { return r == '$' || r == '\u200C' || r == '\u200D' || IsIDContinue(r) },
+	)
+	// IdentPython is XID_Start or '_' followed by XID_Continue.
+	IdentPython = NewIdent(
+		func(r rune) bool { return r == '_' || IsXIDStart(r) },
+		IsXIDContinue,
+	)
+	// IdentSQL is an unquoted SQL identifier. It is ID_Start
+	// or '_' followed by ID_Continue or '$', like PostgreSQL.
+	IdentSQL = NewIdent(
+		func(r rune) bool { return r == '_' || IsIDStart(r) },
+		func(r rune) bool { return r == '$' || IsIDContinue(r) },
+	)
+)
+
+// UtilMatchIdent matches an identifier.
+func (s *Scanner) UtilMatchIdent(id *Ident) bool {
+	ss := *s
+	i := 0
+	for i < len(ss) {
+		if c := ss[i]; c < utf8.RuneSelf {
+			if !id.ascii(c, i == 0) {
+				break
+			}
+			i++
+			continue
+		}
+		f := id.Continue
+		if i == 0 {
+			f = id.Start
+		}
+		r, size := utf8.DecodeRuneInString(ss[i:].String())
+		if !f(r) {
+			break
+		}
+		i += size
+	}
+	if i > 0 {
+		*s = ss[i:]
+		return true
+	}
+	return false
+}
+
+// IsIDStart tells if r has the ID_Start property.
+func IsIDStart(r rune) bool {
+	if r < utf8.RuneSelf {
+		return 'a' <= r|0x20 && r|0x20 <= 'z'
+	}
+	return unicode.In(r, unicode.L, unicode.Nl, unicode.Other_ID_Start) &&
+		!unicode.In(r, unicode.Pattern_Syntax, unicode.Pattern_White_Space)
+}
+
+// IsIDContinue tells if r has the ID_Continue property.
+func IsIDContinue(r rune) bool {
+	if r < utf8.RuneSelf {
+		return 'a' <= r|0x20 && r|0x20 <= 'z' || '0' <= r && r <= '9' || r == '_'
+	}
+	return unicode.In(r, unicode.L, unicode.Nl, unicode.Other_ID_Start,
+		unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc, unicode.Other_ID_Continue) &&
+		!unicode.In(r, unicode.Pattern_Syntax, unicode.Pattern_White_Space)
+}
+
+// IsXIDStart tells if r has the XID_Start property.
+func IsXIDStart(r rune) bool {
+	return IsIDStart(r) && !unicode.Is(notXIDStart, r)
+}
+
+// IsXIDContinue tells if r has the XID_Continue property.
+func IsXIDContinue(r rune) bool {
+	return IsIDContinue(r) && !unicode.Is(notXIDContinue, r)
+}
+
+// notXIDStart are the ID_Start characters
+// that are not XID_Start due to NFKC.
+var notXIDStart = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x037A, Hi: 0x037A, Stride: 1},
+		{Lo: 0x0E33, Hi: 0x0EB3, Stride: 0x80},
+		{Lo: 0x309B, Hi: 0x309C, Stride: 1},
+		{Lo: 0xFC5E, Hi: 0xFC63, Stride: 1},
+		{Lo: 0xFDFA, Hi: 0xFDFB, Stride: 1},
+		{Lo: 0xFE70, Hi: 0xFE7E, Stride: 2},
+		{Lo: 0xFF9E, Hi: 0xFF9F, Stride: 1},
+	},
+}
+
+// notXIDContinue are the ID_Continue characters
+// that are not XID_Continue due to NFKC.
+var notXIDContinue = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x037A, Hi: 0x037A, Stride: 1},
+		{Lo: 0x309B, Hi: 0x309C, Stride: 1},
+		{Lo: 0xFC5E, Hi: 0xFC63, Stride: 1},
+		{Lo: 0xFDFA, Hi: 0xFDFB, Stride: 1},
+		{Lo: 0xFE70, Hi: 0xFE7E, Stride: 2},
+	},
+}
+
+// #endregion Ident
@@ -0,0 +1,110 @@
+package scanner
+
+import (
+	"testing"
+	"unicode"
+)
+
+// #region Ident
+
+func TestScannerUtilMatchIdent(t *testing.T) {
+	tt := []struct {
+		give string
+		when *Ident
+		then bool
+		exp  string
+	}{
+		{give: `abc1 `, when: IdentUAX31, then: true, exp: `abc1`},
+		{give: `1abc`, when: IdentUAX31, then: false, exp: ``},
+		{give: `_abc`, when: IdentUAX31, then: false, exp: ``},
+		{give: `a_b`, when: IdentUAX31, then: true, exp: `a_b`},
+		{give: `café+`, when: IdentUAX31, then: true, exp: `café`},
+		{give: "cafe\u0301+", when: IdentUAX31, then: true, exp: "cafe\u0301"},
+		{give: "\u0301a", when: IdentUAX31, then: false, exp: ""},
+		{give: `世界 `, when: IdentUAX31, then: true, exp: `世界`},
+		{give: "a\u037A", when: IdentUAX31, then: true, exp: "a"},
+		{give: `a٣`, when: IdentUAX31, then: true, exp: `a٣`},
+		{give: ``, when: IdentUAX31, then: false, exp: ``},
+		// Go.
+		{give: `_x1.`, when: IdentGo, then: true, exp: `_x1`},
+		{give: `$x`, when: IdentGo, then: false, exp: ``},
+		// JS.
+		{give: `$x_1$.`, when: IdentJS, then: true, exp: `$x_1$`},
+		{give: "a\u200Db", when: IdentJS, then: true, exp: "a\u200Db"},
+		// Python.
+		{give: `__init__(`, when: IdentPython, then: true, exp: `__init__`},
+		{give: `$x`, when: IdentPython, then: false, exp: ``},
+		// SQL.
+		{give: `my_table$1.`, when: IdentSQL, then: true, exp: `my_table$1`},
+		{give: `$1`, when: IdentSQL, then: false, exp: ``},
+		// Literal, with no tables.
+		{give: `ab1-`, when: &Ident{Start: unicode.IsLetter, Continue: unicode.IsLetter}, then: true, exp: `ab`},
+		{give: `1a`, when: &Ident{Start: unicode.IsLetter, Continue: unicode.IsLetter}, then: false, exp: ``},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		m := s.Mark()
+		assertEqual(t, tc.then, s.UtilMatchIdent(tc.when), tc.give)
+		assertEqual(t, tc.exp, s.Token(m), tc.give)
+	}
+}
+
+func BenchmarkScannerUtilMatchIdent(b *testing.B) {
+	x := Scanner(`abcdefgh `)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilMatchIdent(IdentGo)
+	}
+}
+
+func BenchmarkScannerUtilMatchIdentUnicode(b *testing.B) {
+	x := Scanner(`世界世界 `)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilMatchIdent(IdentUAX31)
+	}
+}
+
+func TestIsXIDStart(t *testing.T) {
+	tt := []struct {
+		give rune
+		then bool
+	}{
+		{give: 'a', then: true},
+		{give: 'Z', then: true},
+		{give: '_', then: false},
+		{give: '1', then: false},
+		{give: '世', then: true},
+		{give: 'ⅰ', then: true},
+		{give: '\u0301', then: false},
+		{give: '℘', then: true},
+		{give: '\u037A', then: false},
+		{give: '\u0E33', then: false},
+		{give: '«', then: false},
+	}
+	for _, tc := range tt {
+		assertEqual(t, tc.then, IsXIDStart(tc.give), string(tc.give))
+	}
+}
+
+func TestIsXIDContinue(t *testing.T) {
+	tt := []struct {
+		give rune
+		then bool
+	}{
+		{give: 'a', then: true},
+		{give: '_', then: true},
+		{give: '1', then: true},
+		{give: '$', then: false},
+		{give: '\u0301', then: true},
+		{give: '\u00B7', then: true},
+		{give: '\u037A', then: false},
+		{give: '\u0E33', then: true},
+		{give: ' ', then: false},
+	}
+	for _, tc := range tt {
+		assertEqual(t, tc.then, IsXIDContinue(tc.give), string(tc.give))
+	}
+}
+
+// #endregion Ident
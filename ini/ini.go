@@ -0,0 +1,414 @@
+// Package ini parses INI and Java .properties files
+// with the scanner. Comments and blank lines are kept,
+// so a file can be edited and written back as it was.
+package ini
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ofabricio/scanner"
+)
+
+// #region File
+
+// File is a parsed file.
+type File struct {
+	// Sections in order. The first is the unnamed section
+	// of the keys before any header, and the only one of
+	// a .properties file.
+	Sections []*Section
+	// Comments is the comment and blank lines after the
+	// last key, verbatim.
+	Comments []string
+
+	props bool
+}
+
+// Section is a section of a file, like [name].
+type Section struct {
+	Name string
+	Line int // 1-based line of the header. Zero if unnamed.
+	Col  int // 1-based column of the header.
+	// Comments is the comment and blank lines
+	// before the header, verbatim.
+	Comments []string
+	Keys     []*Key
+
+	raw string // Header line as in the input.
+}
+
+// Key is a key/value pair.
+type Key struct {
+	Name  string
+	Value string
+	// Sep is the separator, '=' or ':'. In .properties
+	// files a space means there was only whitespace.
+	Sep byte
+	// Comment is the inline comment of an INI key,
+	// delimiter included, like "; default".
+	Comment string
+	// Comments is the comment and blank lines
+	// before the key, verbatim.
+	Comments []string
+	Line     int // 1-based line of the key.
+	Col      int // 1-based column of the key.
+
+	raw string // Lines as in the input. Empty once changed.
+}
+
+// Section returns the first section with a name or nil.
+// The unnamed section is "".
+func (f *File) Section(name string) *Section {
+	for _, s := range f.Sections {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// AddSection appends a section.
+func (f *File) AddSection(name string) *Section {
+	s := &Section{Name: name}
+	f.Sections = append(f.Sections, s)
+	return s
+}
+
+// Key returns the last key with a name or nil,
+// since the last one wins on duplicates.
+func (s *Section) Key(name string) *Key {
+	for i := len(s.Keys) - 1; i >= 0; i-- {
+		if s.Keys[i].Name == name {
+			return s.Keys[i]
+		}
+	}
+	return nil
+}
+
+// Get returns the value of a key.
+func (s *Section) Get(name string) (string, bool) {
+	if k := s.Key(name); k != nil {
+		return k.Value, true
+	}
+	return "", false
+}
+
+// Set sets the value of a key, appending it if missing.
+func (s *Section) Set(name, value string) {
+	if k := s.Key(name); k != nil {
+		k.Value, k.raw = value, ""
+		return
+	}
+	s.Keys = append(s.Keys, &Key{Name: name, Value: value, Sep: '='})
+}
+
+// Delete removes the keys with a name, their
+// comments included. Returns false if missing.
+func (s *Section) Delete(name string) bool {
+	keys := s.Keys[:0]
+	for _, k := range s.Keys {
+		if k.Name != name {
+			keys = append(keys, k)
+		}
+	}
+	ok := len(keys) < len(s.Keys)
+	s.Keys = keys
+	return ok
+}
+
+// String returns the file text. Unchanged lines are
+// written as in the input and changed keys in a
+// canonical form. Lines end with "\n".
+func (f *File) String() string {
+	var b strings.Builder
+	lines := func(ls []string) {
+		for _, l := range ls {
+			b.WriteString(l + "\n")
+		}
+	}
+	for i, s := range f.Sections {
+		lines(s.Comments)
+		switch {
+		case s.raw != "":
+			b.WriteString(s.raw + "\n")
+		case i > 0 || s.Name != "":
+			b.WriteString("[" + s.Name + "]\n")
+		}
+		for _, k := range s.Keys {
+			lines(k.Comments)
+			if k.raw != "" {
+				b.WriteString(k.raw + "\n")
+			} else if f.props {
+				b.WriteString(formatProperty(k) + "\n")
+			} else {
+				b.WriteString(formatKey(k) + "\n")
+			}
+		}
+	}
+	lines(f.Comments)
+	return b.String()
+}
+
+// formatKey returns an INI key line.
+func formatKey(k *Key) string {
+	sep := " = "
+	if k.Sep == ':' {
+		sep = ": "
+	}
+	v := k.Name + sep + quote(k.Value)
+	if k.Comment != "" {
+		v += " " + k.Comment
+	}
+	return v
+}
+
+// quote quotes an INI value if it would
+// not be read back as it is otherwise.
+func quote(v string) string {
+	if v == "" || v == strings.TrimSpace(v) && !strings.ContainsAny(v, ";#\"'\\\n\r") {
+		return v
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(v); i++ {
+		switch c := v[i]; c {
+		case '"', '\\':
+			b.WriteString(`\` + string(c))
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// #endregion File
+
+// #region Errors
+
+var (
+	ErrSection   = errors.New("unclosed section header")
+	ErrSeparator = errors.New("missing key separator")
+	ErrQuote     = errors.New("unclosed quote")
+	ErrTrailing  = errors.New("unexpected trailing text")
+	ErrEscape    = errors.New("invalid escape")
+)
+
+// ParseError is a positioned error.
+type ParseError = scanner.ParseError
+
+// #endregion Errors
+
+// #region Parse
+
+// parser reads a file line by line.
+type parser struct {
+	s        scanner.Scanner
+	line     int             // Line of the current line.
+	bol      scanner.Scanner // Current line.
+	f        *File
+	sec      *Section
+	comments []string
+}
+
+func newParser(src string, props bool) *parser {
+	s := scanner.Scanner(src)
+	f := &File{props: props}
+	return &parser{s: s, f: f, sec: f.AddSection("")}
+}
+
+// next returns the next line, without the line break.
+func (p *parser) next() (scanner.Scanner, bool) {
+	if !p.s.More() {
+		return "", false
+	}
+	p.line++
+	p.bol = p.s
+	if !p.s.MatchUntilByte('\n') {
+		p.s.Advance(len(p.s))
+	}
+	line := p.bol[:len(p.bol)-len(p.s)]
+	p.s.MatchByte('\n')
+	if n := len(line); n > 0 && line[n-1] == '\r' {
+		line = line[:n-1]
+	}
+	p.bol = line
+	return line, true
+}
+
+// col returns the column of a mark in the current line.
+func (p *parser) col(at scanner.Scanner) int {
+	return len(p.bol) - len(at) + 1
+}
+
+func (p *parser) errorf(at scanner.Scanner, err error) error {
+	return &ParseError{Line: p.line, Col: p.col(at), Err: err, Pkg: "ini"}
+}
+
+// key appends a key with the pending comments.
+func (p *parser) key(k *Key) {
+	k.Comments, p.comments = p.comments, nil
+	p.sec.Keys = append(p.sec.Keys, k)
+}
+
+// done moves the pending comments to the file.
+func (p *parser) done() *File {
+	p.f.Comments = p.comments
+	return p.f
+}
+
+// Parse parses an INI file. Lines are either:
+//
+//	[section]
+//	key = value
+//	key: value
+//	; comment
+//	# comment
+//
+// Values may be in double quotes, with escapes, or in
+// single quotes, verbatim. Unquoted values end at an inline comment,
+// a ';' or '#' after a whitespace. A value continues on
+// the next line after a trailing '\' or on lines indented
+// more than its key.
+func Parse(src string) (*File, error) {
+	p := newParser(src, false)
+	var last *Key // Key that indented lines continue.
+	indent := 0   // Indentation of last.
+	for {
+		line, ok := p.next()
+		if !ok {
+			return p.done(), nil
+		}
+		t := line
+		t.MatchWhileByteBy(isSpace)
+		n := len(line) - len(t)
+		switch {
+		case !t.More() || t.EqualByte(';') || t.EqualByte('#'):
+			p.comments = append(p.comments, line.String())
+			last = nil
+		case last != nil && n > indent:
+			last.Value += "\n" + strings.TrimRight(t.String(), " \t")
+			last.raw += "\n" + line.String()
+		case t.EqualByte('['):
+			ini := t
+			t.Next()
+			m := t
+			if !t.MatchUntilByte(']') {
+				return nil, p.errorf(ini, ErrSection)
+			}
+			name := strings.TrimSpace(t.Token(m))
+			t.Next()
+			t.MatchWhileByteBy(isSpace)
+			if t.More() && !t.EqualByte(';') && !t.EqualByte('#') {
+				return nil, p.errorf(t, ErrTrailing)
+			}
+			p.sec = p.f.AddSection(name)
+			p.sec.Line, p.sec.Col, p.sec.raw = p.line, p.col(ini), line.String()
+			p.sec.Comments, p.comments = p.comments, nil
+			last = nil
+		default:
+			k := &Key{Line: p.line, Col: p.col(t)}
+			m := t
+			if !t.MatchUntilAnyByte('=', ':') {
+				return nil, p.errorf(m, ErrSeparator)
+			}
+			k.Name = strings.TrimSpace(t.Token(m))
+			k.Sep = t.Curr()
+			t.Next()
+			t.MatchWhileByteBy(isSpace)
+			raw, err := p.value(k, t, line)
+			if err != nil {
+				return nil, err
+			}
+			k.raw = raw
+			p.key(k)
+			last, indent = nil, n
+			if k.Comment == "" && (len(t) == 0 || t[0] != '"' && t[0] != '\'') {
+				last = k
+			}
+		}
+	}
+}
+
+// value scans the value of a key at t in line, which
+// may take more lines. Returns the raw text of the key.
+func (p *parser) value(k *Key, t, line scanner.Scanner) (string, error) {
+	raw := line.String()
+	if q := t.Curr(); t.More() && (q == '"' || q == '\'') {
+		ini := t
+		var b strings.Builder
+		t.Next()
+		for {
+			if !t.More() {
+				return "", p.errorf(ini, ErrQuote)
+			}
+			c := t.Curr()
+			t.Next()
+			if c == q {
+				break
+			}
+			if c == '\\' && q == '"' && t.More() {
+				c = t.Curr()
+				t.Next()
+				switch c {
+				case 'n':
+					c = '\n'
+				case 'r':
+					c = '\r'
+				case 't':
+					c = '\t'
+				}
+			}
+			b.WriteByte(c)
+		}
+		k.Value = b.String()
+		t.MatchWhileByteBy(isSpace)
+		if t.More() && !t.EqualByte(';') && !t.EqualByte('#') {
+			return "", p.errorf(t, ErrTrailing)
+		}
+		k.Comment = t.String()
+		return raw, nil
+	}
+	var b strings.Builder
+	for {
+		v, comment := splitComment(t.String())
+		if comment != "" || !strings.HasSuffix(v, `\`) {
+			b.WriteString(v)
+			k.Value, k.Comment = b.String(), comment
+			return raw, nil
+		}
+		b.WriteString(v[:len(v)-1])
+		next, ok := p.next()
+		if !ok {
+			k.Value = b.String()
+			return raw, nil
+		}
+		raw += "\n" + next.String()
+		t = next
+		t.MatchWhileByteBy(isSpace)
+	}
+}
+
+// splitComment splits an unquoted value from its inline
+// comment, a ';' or '#' after a whitespace.
+func splitComment(v string) (value, comment string) {
+	for i := 1; i < len(v); i++ {
+		if (v[i] == ';' || v[i] == '#') && isSpace(v[i-1]) {
+			return strings.TrimRight(v[:i], " \t"), v[i:]
+		}
+	}
+	return strings.TrimRight(v, " \t"), ""
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\f'
+}
+
+// #endregion Parse
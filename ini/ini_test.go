@@ -0,0 +1,131 @@
+package ini
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// #region Parse
+
+func TestParse(t *testing.T) {
+	src := `; global
+name = app
+
+[server]
+host = example.com ; the host
+port: 8080
+path = "/a;b" # quoted
+raw = 'C:\dir'
+url = http://x/#frag
+long = a \
+       b
+desc = first
+  second
+
+  third = 3
+[ empty ]
+`
+	f, err := Parse(src)
+	assertEqual(t, nil, err)
+	assertEqual(t, 3, len(f.Sections))
+
+	g := f.Section("")
+	assertEqual(t, []string{"; global"}, g.Keys[0].Comments)
+	v, _ := g.Get("name")
+	assertEqual(t, "app", v)
+
+	s := f.Section("server")
+	assertEqual(t, 4, s.Line)
+	assertEqual(t, 1, s.Col)
+	assertEqual(t, []string{""}, s.Comments)
+	tt := []struct {
+		give string
+		exp  string
+	}{
+		{give: "host", exp: "example.com"},
+		{give: "port", exp: "8080"},
+		{give: "path", exp: "/a;b"},
+		{give: "raw", exp: `C:\dir`},
+		{give: "url", exp: "http://x/#frag"},
+		{give: "long", exp: "a b"},
+		{give: "desc", exp: "first\nsecond"},
+		{give: "third", exp: "3"},
+	}
+	for _, tc := range tt {
+		v, ok := s.Get(tc.give)
+		assertEqual(t, true, ok, tc.give)
+		assertEqual(t, tc.exp, v, tc.give)
+	}
+	host := s.Key("host")
+	assertEqual(t, "; the host", host.Comment)
+	assertEqual(t, 5, host.Line)
+	assertEqual(t, byte(':'), s.Key("port").Sep)
+	assertEqual(t, 3, s.Key("third").Col)
+	assertEqual(t, "empty", f.Sections[2].Name)
+
+	// Round trip.
+	assertEqual(t, src, f.String())
+}
+
+func TestParseEdit(t *testing.T) {
+	src := "# head\n[a]\n; x comment\nx = 1 ; old\ny=2\n\n# tail\n"
+	f, err := Parse(src)
+	assertEqual(t, nil, err)
+	a := f.Section("a")
+	a.Set("x", "a;b")
+	a.Set("z", " pad ")
+	assertEqual(t, true, a.Delete("y"))
+	assertEqual(t, false, a.Delete("y"))
+	f.AddSection("b").Set("k", "v")
+	exp := "# head\n[a]\n; x comment\nx = \"a;b\" ; old\nz = \" pad \"\n[b]\nk = v\n\n# tail\n"
+	assertEqual(t, exp, f.String())
+
+	// Read back.
+	g, err := Parse(f.String())
+	assertEqual(t, nil, err)
+	v, _ := g.Section("a").Get("x")
+	assertEqual(t, "a;b", v)
+	v, _ = g.Section("a").Get("z")
+	assertEqual(t, " pad ", v)
+}
+
+func TestParseError(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+		err  error
+	}{
+		{give: "[a\nb=1", exp: "ini: unclosed section header at 1:1", err: ErrSection},
+		{give: "a=1\n[b] c", exp: "ini: unexpected trailing text at 2:5", err: ErrTrailing},
+		{give: "a=1\n\nkey", exp: "ini: missing key separator at 3:1", err: ErrSeparator},
+		{give: `a = "open`, exp: "ini: unclosed quote at 1:5", err: ErrQuote},
+		{give: `a = "x" y`, exp: "ini: unexpected trailing text at 1:9", err: ErrTrailing},
+	}
+	for _, tc := range tt {
+		_, err := Parse(tc.give)
+		if assertEqual(t, true, err != nil, tc.give) {
+			assertEqual(t, tc.exp, err.Error(), tc.give)
+			assertEqual(t, true, errors.Is(err, tc.err), tc.give)
+		}
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	src := "; c\n[server]\nhost = example.com ; h\nport = 8080\npath = \"/a\"\n[client]\nretries = 3\n"
+	for i := 0; i < b.N; i++ {
+		Parse(src)
+	}
+}
+
+// #endregion Parse
+
+func assertEqual(t *testing.T, exp, got any, msgs ...any) bool {
+	t.Helper()
+	if !reflect.DeepEqual(exp, got) {
+		t.Errorf("\nExp:\n%v\nGot:\n%v\nMsg: %v", exp, got, fmt.Sprint(msgs...))
+		return false
+	}
+	return true
+}
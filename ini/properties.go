@@ -0,0 +1,193 @@
+package ini
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/ofabricio/scanner"
+)
+
+// #region Properties
+
+// ParseProperties parses a Java .properties file. Keys end
+// at an unescaped '=', ':' or whitespace. Comments start
+// with '#' or '!'. A line ending in an odd number of '\'
+// continues on the next one, without its leading whitespace.
+// Keys and values take the escapes \t, \n, \r, \f, \uXXXX
+// and '\' before any other character, which is taken as is.
+// All keys go in the unnamed section.
+func ParseProperties(src string) (*File, error) {
+	p := newParser(src, true)
+	for {
+		line, ok := p.next()
+		if !ok {
+			return p.done(), nil
+		}
+		t := line
+		t.MatchWhileByteBy(isSpace)
+		if !t.More() || t.EqualByte('#') || t.EqualByte('!') {
+			p.comments = append(p.comments, line.String())
+			continue
+		}
+		k := &Key{Line: p.line, Col: p.col(t), Sep: ' '}
+		raw := line.String()
+		// Logical line.
+		text := t.String()
+		for continues(text) {
+			text = text[:len(text)-1]
+			next, ok := p.next()
+			if !ok {
+				break
+			}
+			raw += "\n" + next.String()
+			next.MatchWhileByteBy(isSpace)
+			text += next.String()
+		}
+		s := scanner.Scanner(text)
+		name, err := p.unescape(&s, k, true)
+		if err != nil {
+			return nil, err
+		}
+		s.MatchWhileByteBy(isSpace)
+		if c := s.Curr(); s.More() && (c == '=' || c == ':') {
+			k.Sep = c
+			s.Next()
+			s.MatchWhileByteBy(isSpace)
+		}
+		value, err := p.unescape(&s, k, false)
+		if err != nil {
+			return nil, err
+		}
+		k.Name, k.Value, k.raw = name, value, raw
+		p.key(k)
+	}
+}
+
+// continues tells if a line ends in an odd number of '\'.
+func continues(line string) bool {
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// unescape reads a key, up to a separator, or a value,
+// up to the end. Escape errors are positioned at the key,
+// since a logical line may take many lines.
+func (p *parser) unescape(s *scanner.Scanner, k *Key, key bool) (string, error) {
+	var b strings.Builder
+	for s.More() {
+		c := s.Curr()
+		if key && (c == '=' || c == ':' || isSpace(c)) {
+			break
+		}
+		if c != '\\' {
+			m := *s
+			s.NextRune()
+			b.WriteString(s.Token(m))
+			continue
+		}
+		s.Next()
+		if !s.More() {
+			break
+		}
+		switch c = s.Curr(); c {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			r, ok := utf16Escape(s)
+			if !ok {
+				return "", &ParseError{Line: k.Line, Col: k.Col, Err: ErrEscape, Pkg: "ini"}
+			}
+			b.WriteRune(r)
+			continue
+		default:
+			m := *s
+			s.NextRune()
+			b.WriteString(s.Token(m))
+			continue
+		}
+		s.Next()
+	}
+	return b.String(), nil
+}
+
+// utf16Escape reads an uXXXX escape, or two of
+// them for a UTF-16 surrogate pair.
+func utf16Escape(s *scanner.Scanner) (rune, bool) {
+	unit := func(ss *scanner.Scanner) (rune, bool) {
+		m := *ss
+		if !m.MatchByte('u') {
+			return 0, false
+		}
+		v, ok := m.UtilParseHex(4)
+		if ok {
+			*ss = m
+		}
+		return rune(v), ok
+	}
+	r, ok := unit(s)
+	if !ok {
+		return 0, false
+	}
+	if utf16.IsSurrogate(r) {
+		ss := *s
+		if ss.MatchByte('\\') {
+			if r2, ok := unit(&ss); ok {
+				if d := utf16.DecodeRune(r, r2); d != utf8.RuneError {
+					*s = ss
+					return d, true
+				}
+			}
+		}
+		return utf8.RuneError, true
+	}
+	return r, true
+}
+
+// formatProperty returns a .properties key line.
+func formatProperty(k *Key) string {
+	sep := " = "
+	if k.Sep == ':' {
+		sep = ": "
+	}
+	return escape(k.Name, true) + sep + escape(k.Value, false)
+}
+
+// escape escapes a key or a value.
+func escape(v string, key bool) string {
+	var b strings.Builder
+	for i := 0; i < len(v); i++ {
+		switch c := v[i]; c {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '=', ':', '#', '!', ' ':
+			// A value only loses its leading space.
+			if key || c == ' ' && i == 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// #endregion Properties
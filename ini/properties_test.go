@@ -0,0 +1,103 @@
+package ini
+
+import (
+	"errors"
+	"testing"
+)
+
+// #region Properties
+
+func TestParseProperties(t *testing.T) {
+	src := `# comment
+! also comment
+a=1
+b : 2
+c 3
+  d	=	4
+key\ with\ spaces = v
+e\=q = x\:y
+uni = café 😀
+esc = tab\there\nline\\
+multi = one, \
+        two, \
+        three
+even = ends\\
+f
+`
+	f, err := ParseProperties(src)
+	assertEqual(t, nil, err)
+	assertEqual(t, 1, len(f.Sections))
+	s := f.Sections[0]
+	tt := []struct {
+		give string
+		exp  string
+	}{
+		{give: "a", exp: "1"},
+		{give: "b", exp: "2"},
+		{give: "c", exp: "3"},
+		{give: "d", exp: "4"},
+		{give: "key with spaces", exp: "v"},
+		{give: "e=q", exp: "x:y"},
+		{give: "uni", exp: "café \U0001F600"},
+		{give: "esc", exp: "tab\there\nline\\"},
+		{give: "multi", exp: "one, two, three"},
+		{give: "even", exp: `ends\`},
+		{give: "f", exp: ""},
+	}
+	for _, tc := range tt {
+		v, ok := s.Get(tc.give)
+		assertEqual(t, true, ok, tc.give)
+		assertEqual(t, tc.exp, v, tc.give)
+	}
+	assertEqual(t, []string{"# comment", "! also comment"}, s.Key("a").Comments)
+	assertEqual(t, byte(' '), s.Key("c").Sep)
+	assertEqual(t, byte(':'), s.Key("b").Sep)
+	assertEqual(t, 3, s.Key("d").Col)
+	assertEqual(t, 11, s.Key("multi").Line)
+	assertEqual(t, 15, s.Key("f").Line)
+
+	// Round trip.
+	assertEqual(t, src, f.String())
+}
+
+func TestParsePropertiesEdit(t *testing.T) {
+	f, err := ParseProperties("a=1\n")
+	assertEqual(t, nil, err)
+	s := f.Sections[0]
+	s.Set("a", " lead\\")
+	s.Set("k:e y", "v=1\n#")
+	exp := "a = \\ lead\\\\\nk\\:e\\ y = v=1\\n#\n"
+	assertEqual(t, exp, f.String())
+
+	g, err := ParseProperties(f.String())
+	assertEqual(t, nil, err)
+	v, _ := g.Sections[0].Get("a")
+	assertEqual(t, " lead\\", v)
+	v, _ = g.Sections[0].Get("k:e y")
+	assertEqual(t, "v=1\n#", v)
+}
+
+func TestParsePropertiesSurrogate(t *testing.T) {
+	// A lone surrogate is U+FFFD.
+	f, err := ParseProperties("a=\\uD83Dx\nb=\\uD83D\\u0041")
+	assertEqual(t, nil, err)
+	v, _ := f.Sections[0].Get("a")
+	assertEqual(t, "\uFFFDx", v)
+	v, _ = f.Sections[0].Get("b")
+	assertEqual(t, "\uFFFDA", v)
+}
+
+func TestParsePropertiesError(t *testing.T) {
+	_, err := ParseProperties("a=1\n  b = \\u12G4")
+	assertEqual(t, "ini: invalid escape at 2:3", err.Error())
+	assertEqual(t, true, errors.Is(err, ErrEscape))
+}
+
+func BenchmarkParseProperties(b *testing.B) {
+	src := "# c\na=1\nb : caf\\u00e9\nmulti = one, \\\n  two\n"
+	for i := 0; i < b.N; i++ {
+		ParseProperties(src)
+	}
+}
+
+// #endregion Properties
@@ -0,0 +1,193 @@
+package scanner
+
+// #region Interp
+
+// InterpQuote is a string that may have
+// interpolated expressions.
+type InterpQuote struct {
+	Quote
+	// Interpolation delimiters, like {"${", "}"}.
+	// Empty for plain strings.
+	Interp []Pair
+}
+
+// Interp describes a language with interpolated strings.
+// Strings nest inside expressions, which nest inside strings.
+type Interp struct {
+	// Strings, like the JavaScript template literal.
+	Strings []InterpQuote
+	// Brackets of the expressions, so the interpolation
+	// close is not taken from a nested bracket.
+	Brackets []Pair
+	// Comments of the expressions. Optional.
+	Trivia *Trivia
+}
+
+// InterpPart is a part of an interpolated string.
+type InterpPart struct {
+	// Expr tells if the part is an expression,
+	// otherwise it is a literal fragment.
+	Expr bool
+	// Text is the raw text of the part. Expressions
+	// don't include the interpolation delimiters.
+	Text string
+}
+
+// Interpolated strings of some languages.
+var (
+	InterpJS = Interp{
+		Strings: []InterpQuote{
+			{Quote: Quote{"`", "`", '\\'}, Interp: []Pair{{"${", "}"}}},
+			{Quote: Quote{`"`, `"`, '\\'}},
+			{Quote: Quote{`'`, `'`, '\\'}},
+		},
+		Brackets: []Pair{{"(", ")"}, {"[", "]"}, {"{", "}"}},
+		Trivia:   &TriviaC,
+	}
+	InterpKotlin = Interp{
+		Strings: []InterpQuote{
+			{Quote: Quote{`"""`, `"""`, 0}, Interp: []Pair{{"${", "}"}}},
+			{Quote: Quote{`"`, `"`, '\\'}, Interp: []Pair{{"${", "}"}}},
+			{Quote: Quote{`'`, `'`, '\\'}},
+		},
+		Brackets: []Pair{{"(", ")"}, {"[", "]"}, {"{", "}"}},
+		Trivia:   &TriviaC,
+	}
+	InterpShell = Interp{
+		Strings: []InterpQuote{
+			{Quote: Quote{`"`, `"`, '\\'}, Interp: []Pair{{"$(", ")"}, {"${", "}"}}},
+			{Quote: Quote{`'`, `'`, 0}},
+			{Quote: Quote{"`", "`", '\\'}},
+		},
+		Brackets: []Pair{{"(", ")"}, {"{", "}"}},
+	}
+)
+
+// UtilMatchInterp matches an interpolated string and calls f
+// with its literal fragments and expressions in order. Empty
+// fragments are skipped. Nested strings stay in the expression
+// text, so f can match them again. It returns false and no error
+// if there is no string at the start, or a BalanceError on the
+// first mismatched bracket or on the innermost unclosed delimiter.
+func (s *Scanner) UtilMatchInterp(in *Interp, f func(InterpPart)) (bool, error) {
+	ss := *s
+	q := in.quote(ss)
+	if q == nil {
+		return false, nil
+	}
+	// A mode is either a string or an expression.
+	type mode struct {
+		str   *InterpQuote // String mode.
+		close string       // Expression mode.
+		pos   int
+		delim string
+	}
+	var buf [16]mode
+	stack := append(buf[:0], mode{str: q, pos: 0, delim: q.Open})
+	ini := len(q.Open) // Start of the current part.
+	emit := func(expr bool, end int) {
+		if end > ini && f != nil {
+			f(InterpPart{Expr: expr, Text: ss[ini:end].String()})
+		}
+	}
+	for i := len(q.Open); i < len(ss); {
+		rest := ss[i:]
+		top := stack[len(stack)-1]
+		if top.str != nil {
+			if e := top.str.Escape; e != 0 && rest[0] == e {
+				i += 2
+				continue
+			}
+			if rest.Equal(top.str.Close) {
+				if stack = stack[:len(stack)-1]; len(stack) == 0 {
+					emit(false, i)
+					*s = ss[i+len(top.str.Close):]
+					return true, nil
+				}
+				i += len(top.str.Close)
+				continue
+			}
+			if p, ok := top.str.interp(rest); ok {
+				if len(stack) == 1 {
+					emit(false, i)
+					ini = i + len(p.Open)
+				}
+				stack = append(stack, mode{close: p.Close, pos: i, delim: p.Open})
+				i += len(p.Open)
+				continue
+			}
+			i++
+			continue
+		}
+		if rest.Equal(top.close) {
+			if stack = stack[:len(stack)-1]; len(stack) == 1 {
+				emit(true, i)
+				ini = i + len(top.close)
+			}
+			i += len(top.close)
+			continue
+		}
+		if q := in.quote(rest); q != nil {
+			stack = append(stack, mode{str: q, pos: i, delim: q.Open})
+			i += len(q.Open)
+			continue
+		}
+		if t := in.Trivia; t != nil {
+			if t.line(rest) {
+				i += rest.matchLine()
+				continue
+			}
+			if p, ok := t.block(rest); ok {
+				n, ok := rest.matchBlock(p, t.Nested)
+				if !ok {
+					return false, &BalanceError{Pos: i, Delim: p.Open, Err: ErrUnclosed}
+				}
+				i += n
+				continue
+			}
+		}
+		if p, ok := in.bracket(rest, true); ok {
+			stack = append(stack, mode{close: p.Close, pos: i, delim: p.Open})
+			i += len(p.Open)
+			continue
+		}
+		if p, ok := in.bracket(rest, false); ok {
+			return false, &BalanceError{Pos: i, Delim: p.Close, Err: ErrMismatched}
+		}
+		i++
+	}
+	top := stack[len(stack)-1]
+	return false, &BalanceError{Pos: top.pos, Delim: top.delim, Err: ErrUnclosed}
+}
+
+// quote returns the string that opens at ss or nil.
+func (in *Interp) quote(ss Scanner) *InterpQuote {
+	for i := range in.Strings {
+		if ss.Equal(in.Strings[i].Open) {
+			return &in.Strings[i]
+		}
+	}
+	return nil
+}
+
+// bracket returns the bracket that opens or closes at ss.
+func (in *Interp) bracket(ss Scanner, open bool) (Pair, bool) {
+	for _, p := range in.Brackets {
+		if open && ss.Equal(p.Open) || !open && ss.Equal(p.Close) {
+			return p, true
+		}
+	}
+	return Pair{}, false
+}
+
+// interp returns the interpolation that opens at ss.
+func (q *InterpQuote) interp(ss Scanner) (Pair, bool) {
+	for _, p := range q.Interp {
+		if ss.Equal(p.Open) {
+			return p, true
+		}
+	}
+	return Pair{}, false
+}
+
+// #endregion Interp
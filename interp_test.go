@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"errors"
+	"testing"
+)
+
+// #region Interp
+
+func TestScannerUtilMatchInterp(t *testing.T) {
+	tt := []struct {
+		give string
+		when Interp
+		then []InterpPart
+		exp  string
+	}{
+		{
+			give: "`a ${b + `c ${d}`} e` x", when: InterpJS,
+			then: []InterpPart{{false, "a "}, {true, "b + `c ${d}`"}, {false, " e"}},
+			exp:  " x",
+		},
+		{
+			give: "`${a}${b}`", when: InterpJS,
+			then: []InterpPart{{true, "a"}, {true, "b"}},
+		},
+		{
+			give: "`a ${ {x: '}'}.x /* } */ } \\${b}`", when: InterpJS,
+			then: []InterpPart{{false, "a "}, {true, " {x: '}'}.x /* } */ "}, {false, " \\${b}"}},
+		},
+		{
+			give: "'a ${b}'", when: InterpJS,
+			then: []InterpPart{{false, "a ${b}"}},
+		},
+		{
+			give: "``", when: InterpJS,
+		},
+		{
+			give: `"a ${x.y("}")} b"`, when: InterpKotlin,
+			then: []InterpPart{{false, "a "}, {true, `x.y("}")`}, {false, " b"}},
+		},
+		{
+			give: `"""a "${x}" b"""`, when: InterpKotlin,
+			then: []InterpPart{{false, `a "`}, {true, "x"}, {false, `" b`}},
+		},
+		{
+			give: `"a $(cmd "nested $(x)") ${v}" y`, when: InterpShell,
+			then: []InterpPart{{false, "a "}, {true, `cmd "nested $(x)"`}, {false, " "}, {true, "v"}},
+			exp:  " y",
+		},
+	}
+	for _, tc := range tt {
+		var got []InterpPart
+		s := Scanner(tc.give)
+		ok, err := s.UtilMatchInterp(&tc.when, func(p InterpPart) {
+			got = append(got, p)
+		})
+		assertEqual(t, true, ok, tc.give)
+		assertEqual(t, nil, err, tc.give)
+		assertEqual(t, tc.then, got, tc.give)
+		assertEqual(t, tc.exp, s.String(), tc.give)
+	}
+}
+
+func TestScannerUtilMatchInterpError(t *testing.T) {
+	tt := []struct {
+		give string
+		err  error
+		pos  int
+	}{
+		{give: "`a ${b", err: ErrUnclosed, pos: 3},
+		{give: "`a ${b + `c", err: ErrUnclosed, pos: 9},
+		{give: "`a ${(b}`", err: ErrMismatched, pos: 7},
+		{give: "`a ${b )}`", err: ErrMismatched, pos: 7},
+		{give: "`a", err: ErrUnclosed, pos: 0},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		ok, err := s.UtilMatchInterp(&InterpJS, nil)
+		assertEqual(t, false, ok, tc.give)
+		var e *BalanceError
+		assertEqual(t, true, errors.As(err, &e), tc.give)
+		assertEqual(t, tc.err, e.Err, tc.give)
+		assertEqual(t, tc.pos, e.Pos, tc.give)
+		assertEqual(t, tc.give, s.String(), tc.give)
+	}
+	s := Scanner("x`a`")
+	ok, err := s.UtilMatchInterp(&InterpJS, nil)
+	assertEqual(t, false, ok)
+	assertEqual(t, nil, err)
+}
+
+func BenchmarkScannerUtilMatchInterp(b *testing.B) {
+	x := Scanner("`a ${b + `c ${d}`} e`")
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilMatchInterp(&InterpJS, func(InterpPart) {})
+	}
+}
+
+// #endregion Interp
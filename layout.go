@@ -0,0 +1,214 @@
+package scanner
+
+import (
+	"errors"
+	"strconv"
+)
+
+// #region Layout
+
+// LayoutKind is a synthetic token of indentation-sensitive
+// languages, like Python's INDENT, DEDENT and NEWLINE.
+type LayoutKind int
+
+const (
+	LayoutNone    LayoutKind = iota // The line content follows.
+	LayoutNewline                   // A line ended.
+	LayoutIndent                    // The indentation increased.
+	LayoutDedent                    // The indentation decreased one level.
+	LayoutEOF                       // The input ended.
+)
+
+func (k LayoutKind) String() string {
+	switch k {
+	case LayoutNone:
+		return "NONE"
+	case LayoutNewline:
+		return "NEWLINE"
+	case LayoutIndent:
+		return "INDENT"
+	case LayoutDedent:
+		return "DEDENT"
+	case LayoutEOF:
+		return "EOF"
+	}
+	return "LayoutKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Errors of LayoutError.
+var (
+	ErrDedent = errors.New("dedent does not match any outer indentation level")
+	ErrTab    = errors.New("tab in indentation")
+)
+
+// LayoutError records an indentation error.
+type LayoutError struct {
+	Pos int   // Byte offset, from the start of the input, of the error.
+	Err error // Either ErrDedent or ErrTab.
+}
+
+func (e *LayoutError) Error() string {
+	return "scanner: " + e.Err.Error() + " at " + strconv.Itoa(e.Pos)
+}
+
+func (e *LayoutError) Unwrap() error {
+	return e.Err
+}
+
+// Layout measures the indentation of lines
+// and emits LayoutKind tokens.
+//
+// Call Next at the start of the input and whenever
+// the scanner reaches a line break. It returns the
+// layout tokens one by one, skipping blank and
+// comment lines, until LayoutNone tells the scanner
+// is at the line content.
+type Layout struct {
+	// TabSize makes a tab advance to the next
+	// multiple of it. Zero rejects tabs.
+	TabSize int
+	// Trivia line comments make comment lines
+	// count as blank lines. Optional.
+	Trivia *Trivia
+
+	src     Scanner // Input start, for error positions.
+	levels  []int   // Indentation stack.
+	dedents int     // Pending dedents.
+	bol     bool    // At the beginning of a line.
+	started bool
+}
+
+// Next returns the next layout token.
+func (l *Layout) Next(s *Scanner) (LayoutKind, error) {
+	if !l.started {
+		l.started = true
+		l.bol = true
+		l.src = *s
+		l.levels = append(l.levels[:0], 0)
+	}
+	if l.dedents > 0 {
+		l.dedents--
+		return LayoutDedent, nil
+	}
+	if !l.bol {
+		if s.Match("\r\n") || s.MatchByte('\n') {
+			l.bol = true
+			return LayoutNewline, nil
+		}
+		if s.More() {
+			return LayoutNone, nil
+		}
+		l.bol = true
+		return LayoutNewline, nil
+	}
+	// Skip blank and comment lines.
+	col, n := 0, 0
+	for {
+		var err error
+		if col, n, err = l.measure(*s); err != nil {
+			return LayoutNone, err
+		}
+		if !l.blank((*s)[n:]) {
+			break
+		}
+		ss := (*s)[n:]
+		ss = ss[ss.matchLine():]
+		if !ss.Match("\r\n") && !ss.MatchByte('\n') {
+			// Comment line at EOF.
+			*s = ss
+			break
+		}
+		*s = ss
+	}
+	// EOF closes all the levels.
+	if !s.More() || len(*s) == n {
+		*s = (*s)[len(*s):]
+		if len(l.levels) > 1 {
+			l.dedents = len(l.levels) - 2
+			l.levels = l.levels[:1]
+			return LayoutDedent, nil
+		}
+		return LayoutEOF, nil
+	}
+	top := l.levels[len(l.levels)-1]
+	switch {
+	case col > top:
+		l.levels = append(l.levels, col)
+		*s = (*s)[n:]
+		l.bol = false
+		return LayoutIndent, nil
+	case col < top:
+		k := len(l.levels) - 1
+		for k > 0 && l.levels[k] > col {
+			k--
+		}
+		if l.levels[k] != col {
+			return LayoutNone, &LayoutError{Pos: l.pos(*s) + n, Err: ErrDedent}
+		}
+		l.dedents = len(l.levels) - 1 - k - 1
+		l.levels = l.levels[:k+1]
+		*s = (*s)[n:]
+		l.bol = false
+		return LayoutDedent, nil
+	}
+	*s = (*s)[n:]
+	l.bol = false
+	return LayoutNone, nil
+}
+
+// Push opens an indentation level at col, if it is
+// deeper than the current one. It is for blocks that
+// start in the middle of a line, like YAML's "- a: 1",
+// whose next lines are indented to the block column.
+// Returns false if no level was opened.
+func (l *Layout) Push(col int) bool {
+	if len(l.levels) == 0 || col <= l.levels[len(l.levels)-1] {
+		return false
+	}
+	l.levels = append(l.levels, col)
+	return true
+}
+
+// Level returns the current indentation level.
+func (l *Layout) Level() int {
+	if len(l.levels) == 0 {
+		return 0
+	}
+	return len(l.levels) - 1
+}
+
+// measure returns the column and the length
+// of the indentation at the start of ss.
+func (l *Layout) measure(ss Scanner) (col, n int, err error) {
+	for ; n < len(ss); n++ {
+		switch ss[n] {
+		case ' ':
+			col++
+		case '\t':
+			if l.TabSize <= 0 {
+				return 0, 0, &LayoutError{Pos: l.pos(ss) + n, Err: ErrTab}
+			}
+			col += l.TabSize - col%l.TabSize
+		default:
+			return col, n, nil
+		}
+	}
+	return col, n, nil
+}
+
+// blank tells if the rest of the line is empty or a comment.
+func (l *Layout) blank(ss Scanner) bool {
+	if len(ss) == 0 {
+		return false
+	}
+	if ss[0] == '\n' || ss[0] == '\r' {
+		return true
+	}
+	return l.Trivia != nil && l.Trivia.line(ss)
+}
+
+func (l *Layout) pos(ss Scanner) int {
+	return len(l.src) - len(ss)
+}
+
+// #endregion Layout
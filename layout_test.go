@@ -0,0 +1,99 @@
+package scanner
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// #region Layout
+
+func TestLayoutNext(t *testing.T) {
+	tt := []struct {
+		give string
+		when Layout
+		then string
+		err  error
+		pos  int
+	}{
+		{give: "a", then: "a NEWLINE EOF"},
+		{give: "a\nb\n", then: "a NEWLINE b NEWLINE EOF"},
+		{give: "a\n  b\n  c\nd", then: "a NEWLINE INDENT b NEWLINE c NEWLINE DEDENT d NEWLINE EOF"},
+		{give: "a\n  b\n    c\n", then: "a NEWLINE INDENT b NEWLINE INDENT c NEWLINE DEDENT DEDENT EOF"},
+		{give: "a\n  b\n    c\nd\n", then: "a NEWLINE INDENT b NEWLINE INDENT c NEWLINE DEDENT DEDENT d NEWLINE EOF"},
+		{give: "\n\na\r\n\r\n  b\r\n", then: "a NEWLINE INDENT b NEWLINE DEDENT EOF"},
+		{give: "a\n  b\n\n    \n  # x\n  c", when: Layout{Trivia: &TriviaShell}, then: "a NEWLINE INDENT b NEWLINE c NEWLINE DEDENT EOF"},
+		{give: "a\n  b\n# x", when: Layout{Trivia: &TriviaShell}, then: "a NEWLINE INDENT b NEWLINE DEDENT EOF"},
+		{give: "a\n\tb\n        c", when: Layout{TabSize: 8}, then: "a NEWLINE INDENT b NEWLINE c NEWLINE DEDENT EOF"},
+		{give: "", then: "EOF"},
+		{give: "a\n    b\n  c", then: "a NEWLINE INDENT b NEWLINE", err: ErrDedent, pos: 10},
+		{give: "a\n\tb", then: "a NEWLINE", err: ErrTab, pos: 2},
+	}
+	for _, tc := range tt {
+		var got []string
+		s := Scanner(tc.give)
+		l := tc.when
+		for {
+			k, err := l.Next(&s)
+			if err != nil {
+				var e *LayoutError
+				assertEqual(t, true, errors.As(err, &e), tc.give)
+				assertEqual(t, tc.err, e.Err, tc.give)
+				assertEqual(t, tc.pos, e.Pos, tc.give)
+				break
+			}
+			if k == LayoutNone {
+				got = append(got, s.TokenFor(func() bool {
+					return s.MatchUntilAnyByte('\r', '\n') || s.MatchWhileByteBy(func(byte) bool { return true })
+				}))
+				continue
+			}
+			got = append(got, k.String())
+			if k == LayoutEOF {
+				assertEqual(t, nil, tc.err, tc.give)
+				break
+			}
+		}
+		assertEqual(t, tc.then, strings.Join(got, " "), tc.give)
+	}
+}
+
+func TestLayoutPush(t *testing.T) {
+	s := Scanner("- a\n    b\n  c\nd")
+	var l Layout
+	var got []string
+	for {
+		k, err := l.Next(&s)
+		assertEqual(t, nil, err)
+		if k == LayoutEOF {
+			break
+		}
+		if k == LayoutNone {
+			if s.MatchByte('-') {
+				assertEqual(t, true, l.Push(2))
+				assertEqual(t, false, l.Push(1))
+				s.MatchByte(' ')
+			}
+			got = append(got, s.TokenFor(func() bool { return s.MatchUntilByte('\n') || s.MatchWhileByteBy(func(byte) bool { return true }) }))
+			continue
+		}
+		got = append(got, k.String())
+	}
+	assertEqual(t, "a NEWLINE INDENT b NEWLINE DEDENT c NEWLINE DEDENT d NEWLINE", strings.Join(got, " "))
+	assertEqual(t, 0, l.Level())
+}
+
+func BenchmarkLayoutNext(b *testing.B) {
+	x := Scanner("a\n  b\n    c\nd\n")
+	for i := 0; i < b.N; i++ {
+		s := x
+		var l Layout
+		for k, _ := l.Next(&s); k != LayoutEOF; k, _ = l.Next(&s) {
+			if k == LayoutNone {
+				s.MatchUntilByte('\n')
+			}
+		}
+	}
+}
+
+// #endregion Layout
@@ -0,0 +1,89 @@
+package scanner
+
+import "fmt"
+
+// #region Lexer
+
+// StateFn is a lexer state. It scans the input,
+// emits tokens and returns the next state.
+// Returning nil ends the lexing.
+type StateFn func(*Lexer) StateFn
+
+// Lexer is a state function lexer, as in Rob Pike's
+// "Lexical Scanning in Go". States scan the input with
+// the embedded Scanner and emit the text scanned since
+// the last emit. Tokens are pulled with NextToken.
+type Lexer struct {
+	Scanner
+	src   Scanner // Input start.
+	start Scanner // Start of the current token.
+	state StateFn
+	buf   []Token // Lookahead.
+}
+
+// NewLexer returns a lexer for src starting at a state.
+func NewLexer(src string, state StateFn) *Lexer {
+	s := Scanner(src)
+	return &Lexer{Scanner: s, src: s, start: s, state: state}
+}
+
+// Emit emits a token of kind k with the text
+// scanned since the last Emit or Ignore.
+func (l *Lexer) Emit(k Kind) {
+	l.buf = append(l.buf, Token{Kind: k, Text: l.Scanner.Token(l.start), Span: l.src.Span(l.start, l.Scanner)})
+	l.start = l.Scanner
+}
+
+// Ignore drops the text scanned since
+// the last Emit or Ignore.
+func (l *Lexer) Ignore() {
+	l.start = l.Scanner
+}
+
+// Errorf emits an error token at the current
+// position and returns nil to end the lexing.
+func (l *Lexer) Errorf(format string, args ...any) StateFn {
+	l.buf = append(l.buf, Token{Kind: KindError, Text: fmt.Sprintf(format, args...), Span: Span{l.Pos(), l.Pos()}})
+	return nil
+}
+
+// Pending returns the text scanned since
+// the last Emit or Ignore.
+func (l *Lexer) Pending() string {
+	return l.Scanner.Token(l.start)
+}
+
+// Start returns the offset of the current token.
+func (l *Lexer) Start() int {
+	return len(l.src) - len(l.start)
+}
+
+// Pos returns the current offset.
+func (l *Lexer) Pos() int {
+	return len(l.src) - len(l.Scanner)
+}
+
+// NextToken returns the next token, running
+// the states as needed. After the lexing ends
+// it keeps returning an EOF token.
+func (l *Lexer) NextToken() Token {
+	t := l.PeekToken(0)
+	if len(l.buf) > 0 {
+		l.buf = l.buf[:copy(l.buf, l.buf[1:])]
+	}
+	return t
+}
+
+// PeekToken returns the k-th next token
+// without consuming it. Zero is the next one.
+func (l *Lexer) PeekToken(k int) Token {
+	for len(l.buf) <= k && l.state != nil {
+		l.state = l.state(l)
+	}
+	if k < len(l.buf) {
+		return l.buf[k]
+	}
+	return Token{Kind: KindEOF, Span: Span{len(l.src), len(l.src)}}
+}
+
+// #endregion Lexer
@@ -0,0 +1,111 @@
+package scanner
+
+import "testing"
+
+// #region Lexer
+
+var (
+	kindIdent  = NewKind("Ident")
+	kindNumber = NewKind("Number")
+	kindOp     = NewKind("Op")
+)
+
+// lexAny is the state of a tiny test language.
+func lexAny(l *Lexer) StateFn {
+	l.WS()
+	l.Ignore()
+	switch {
+	case !l.More():
+		return nil
+	case l.UtilMatchIdent(IdentGo):
+		l.Emit(kindIdent)
+	case l.UtilMatchInteger():
+		l.Emit(kindNumber)
+	case l.MatchByte('=') || l.MatchByte(';'):
+		l.Emit(kindOp)
+	default:
+		return l.Errorf("unexpected %q", l.Curr())
+	}
+	return lexAny
+}
+
+func TestLexerNextToken(t *testing.T) {
+	tt := []struct {
+		give string
+		then []Token
+	}{
+		{
+			give: "a = 12;",
+			then: []Token{
+				{Kind: kindIdent, Text: "a", Span: Span{0, 1}},
+				{Kind: kindOp, Text: "=", Span: Span{2, 3}},
+				{Kind: kindNumber, Text: "12", Span: Span{4, 6}},
+				{Kind: kindOp, Text: ";", Span: Span{6, 7}},
+				{Kind: KindEOF, Text: "", Span: Span{7, 7}},
+			},
+		},
+		{
+			give: " a ?",
+			then: []Token{
+				{Kind: kindIdent, Text: "a", Span: Span{1, 2}},
+				{Kind: KindError, Text: "unexpected '?'", Span: Span{3, 3}},
+				{Kind: KindEOF, Text: "", Span: Span{4, 4}},
+			},
+		},
+		{
+			give: "",
+			then: []Token{
+				{Kind: KindEOF, Text: "", Span: Span{0, 0}},
+			},
+		},
+	}
+	for _, tc := range tt {
+		var got []Token
+		l := NewLexer(tc.give, lexAny)
+		for {
+			tok := l.NextToken()
+			got = append(got, tok)
+			if tok.Kind == KindEOF {
+				break
+			}
+		}
+		assertEqual(t, tc.then, got, tc.give)
+		assertEqual(t, KindEOF, l.NextToken().Kind, tc.give)
+	}
+}
+
+func TestLexerPeekToken(t *testing.T) {
+	l := NewLexer("a = 1", lexAny)
+	assertEqual(t, "=", l.PeekToken(1).Text)
+	assertEqual(t, "1", l.PeekToken(2).Text)
+	assertEqual(t, KindEOF, l.PeekToken(3).Kind)
+	assertEqual(t, "a", l.NextToken().Text)
+	assertEqual(t, "1", l.PeekToken(1).Text)
+	assertEqual(t, "=", l.NextToken().Text)
+	assertEqual(t, "1", l.NextToken().Text)
+	assertEqual(t, KindEOF, l.NextToken().Kind)
+}
+
+func TestLexerPending(t *testing.T) {
+	l := NewLexer("abc def", func(l *Lexer) StateFn {
+		l.MatchUntilByte(' ')
+		assertEqual(t, "abc", l.Pending())
+		assertEqual(t, 0, l.Start())
+		assertEqual(t, 3, l.Pos())
+		l.Ignore()
+		assertEqual(t, "", l.Pending())
+		assertEqual(t, 3, l.Start())
+		return nil
+	})
+	assertEqual(t, KindEOF, l.NextToken().Kind)
+}
+
+func BenchmarkLexerNextToken(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		l := NewLexer("a = 12; b = 34;", lexAny)
+		for l.NextToken().Kind != KindEOF {
+		}
+	}
+}
+
+// #endregion Lexer
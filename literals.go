@@ -0,0 +1,142 @@
+package scanner
+
+import "unicode/utf8"
+
+// #region Literals
+
+// Literals is a compiled set of literal strings, like
+// keywords and operators. It is a trie, so all the
+// literals are tested in a single pass.
+type Literals struct {
+	nodes []litNode
+	fold  bool
+}
+
+type litNode struct {
+	id   int     // Literal index or -1.
+	lo   byte    // Byte of next[0].
+	next []int32 // Child node by byte, 0 if none.
+}
+
+// NewLiterals compiles a set of literals.
+// The id of a literal is its index in lits.
+// Empty and repeated literals are ignored.
+func NewLiterals(lits ...string) *Literals {
+	return newLiterals(lits, false)
+}
+
+// NewLiteralsFold compiles a set of literals that
+// matches ignoring ASCII case, like SQL keywords.
+func NewLiteralsFold(lits ...string) *Literals {
+	return newLiterals(lits, true)
+}
+
+func newLiterals(lits []string, fold bool) *Literals {
+	type node struct {
+		id   int
+		next map[byte]int32
+	}
+	nodes := []node{{id: -1}}
+	for id, lit := range lits {
+		n := 0
+		for i := 0; i < len(lit); i++ {
+			c := lit[i]
+			if fold {
+				c = lower(c)
+			}
+			if nodes[n].next == nil {
+				nodes[n].next = map[byte]int32{}
+			}
+			next, ok := nodes[n].next[c]
+			if !ok {
+				next = int32(len(nodes))
+				nodes[n].next[c] = next
+				nodes = append(nodes, node{id: -1})
+			}
+			n = int(next)
+		}
+		if n > 0 && nodes[n].id < 0 {
+			nodes[n].id = id
+		}
+	}
+	set := &Literals{nodes: make([]litNode, len(nodes)), fold: fold}
+	for i, n := range nodes {
+		set.nodes[i].id = n.id
+		if len(n.next) == 0 {
+			continue
+		}
+		lo, hi := byte(255), byte(0)
+		for c := range n.next {
+			if c < lo {
+				lo = c
+			}
+			if c > hi {
+				hi = c
+			}
+		}
+		set.nodes[i].lo = lo
+		set.nodes[i].next = make([]int32, int(hi-lo)+1)
+		for c, next := range n.next {
+			set.nodes[i].next[c-lo] = next
+		}
+	}
+	return set
+}
+
+// longest returns the id and the length
+// of the longest literal prefix of ss.
+func (set *Literals) longest(ss Scanner) (id, size int) {
+	id = -1
+	n := &set.nodes[0]
+	for i := 0; i < len(ss); i++ {
+		c := ss[i]
+		if set.fold {
+			c = lower(c)
+		}
+		if c < n.lo || int(c-n.lo) >= len(n.next) || n.next[c-n.lo] == 0 {
+			break
+		}
+		n = &set.nodes[n.next[c-n.lo]]
+		if n.id >= 0 {
+			id, size = n.id, i+1
+		}
+	}
+	return id, size
+}
+
+// MatchLongest matches the longest literal of a set,
+// so "===" matches "===" instead of "=" or "==".
+// Returns the id of the matched literal.
+func (s *Scanner) MatchLongest(set *Literals) (id int, ok bool) {
+	id, size := set.longest(*s)
+	if id < 0 {
+		return -1, false
+	}
+	*s = (*s)[size:]
+	return id, true
+}
+
+// MatchKeyword matches the longest literal of a set
+// that is not followed by an identifier character,
+// so "if" matches "if (" but not "iffy". A nil ident
+// skips that check, like MatchLongest.
+// Returns the id of the matched literal.
+func (s *Scanner) MatchKeyword(set *Literals, ident *Ident) (id int, ok bool) {
+	id, size := set.longest(*s)
+	if id < 0 {
+		return -1, false
+	}
+	if rest := (*s)[size:]; len(rest) > 0 && ident != nil {
+		if c := rest[0]; c < utf8.RuneSelf {
+			if ident.ascii(c, false) {
+				return -1, false
+			}
+		} else if ident.Continue(rest.CurrRune()) {
+			return -1, false
+		}
+	}
+	*s = (*s)[size:]
+	return id, true
+}
+
+// #endregion Literals
@@ -0,0 +1,117 @@
+package scanner
+
+import (
+	"testing"
+	"unicode"
+)
+
+// #region Literals
+
+func TestScannerMatchLongest(t *testing.T) {
+	ops := NewLiterals("=", "==", "===", "!=", "!==", "<", "<=", "<<=")
+	tt := []struct {
+		give string
+		then int
+		ok   bool
+		exp  string
+	}{
+		{give: `= a`, then: 0, ok: true, exp: ` a`},
+		{give: `== a`, then: 1, ok: true, exp: ` a`},
+		{give: `=== a`, then: 2, ok: true, exp: ` a`},
+		{give: `==== a`, then: 2, ok: true, exp: `= a`},
+		{give: `!==`, then: 4, ok: true, exp: ``},
+		{give: `<<`, then: 5, ok: true, exp: `<`},
+		{give: `<<=`, then: 7, ok: true, exp: ``},
+		{give: `!`, then: -1, ok: false, exp: `!`},
+		{give: `a`, then: -1, ok: false, exp: `a`},
+		{give: ``, then: -1, ok: false, exp: ``},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		id, ok := s.MatchLongest(ops)
+		assertEqual(t, tc.then, id, tc)
+		assertEqual(t, tc.ok, ok, tc)
+		assertEqual(t, tc.exp, s.String(), tc)
+	}
+}
+
+func TestScannerMatchLongestFold(t *testing.T) {
+	kws := NewLiteralsFold("select", "from", "where", "order by")
+	tt := []struct {
+		give string
+		then int
+		ok   bool
+		exp  string
+	}{
+		{give: `SELECT *`, then: 0, ok: true, exp: ` *`},
+		{give: `Select *`, then: 0, ok: true, exp: ` *`},
+		{give: `ORDER BY x`, then: 3, ok: true, exp: ` x`},
+		{give: `SELEC`, then: -1, ok: false, exp: `SELEC`},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		id, ok := s.MatchLongest(kws)
+		assertEqual(t, tc.then, id, tc)
+		assertEqual(t, tc.ok, ok, tc)
+		assertEqual(t, tc.exp, s.String(), tc)
+	}
+}
+
+func BenchmarkScannerMatchLongest(b *testing.B) {
+	ops := NewLiterals("=", "==", "===", "!=", "!==", "<", "<=", "<<=")
+	x := Scanner(`=== a`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.MatchLongest(ops)
+	}
+}
+
+func TestScannerMatchKeyword(t *testing.T) {
+	kws := NewLiterals("if", "in", "int", "for")
+	tt := []struct {
+		give string
+		then int
+		ok   bool
+		exp  string
+	}{
+		{give: `if (`, then: 0, ok: true, exp: ` (`},
+		{give: `if(`, then: 0, ok: true, exp: `(`},
+		{give: `if`, then: 0, ok: true, exp: ``},
+		{give: `iffy`, then: -1, ok: false, exp: `iffy`},
+		{give: `if_`, then: -1, ok: false, exp: `if_`},
+		{give: `if2`, then: -1, ok: false, exp: `if2`},
+		{give: `ifé`, then: -1, ok: false, exp: `ifé`},
+		{give: `int x`, then: 2, ok: true, exp: ` x`},
+		{give: `inx`, then: -1, ok: false, exp: `inx`},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		id, ok := s.MatchKeyword(kws, IdentGo)
+		assertEqual(t, tc.then, id, tc)
+		assertEqual(t, tc.ok, ok, tc)
+		assertEqual(t, tc.exp, s.String(), tc)
+	}
+}
+
+func TestScannerMatchKeywordIdent(t *testing.T) {
+	kws := NewLiterals("if")
+	s := Scanner(`iffy`)
+	id, ok := s.MatchKeyword(kws, nil)
+	assertEqual(t, 0, id)
+	assertEqual(t, true, ok)
+	assertEqual(t, `fy`, s.String())
+	s = Scanner(`iffy`)
+	_, ok = s.MatchKeyword(kws, &Ident{Start: unicode.IsLetter, Continue: unicode.IsLetter})
+	assertEqual(t, false, ok)
+}
+
+func BenchmarkScannerMatchKeyword(b *testing.B) {
+	kws := NewLiterals("if", "in", "int", "for")
+	x := Scanner(`int x`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.MatchKeyword(kws, IdentGo)
+	}
+}
+
+// #endregion Literals
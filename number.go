@@ -0,0 +1,728 @@
+package scanner
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"unicode/utf8"
+)
+
+// #region Number
+
+// UtilMatchInteger matches a decimal integer
+// with an optional sign. Eg: 12, -12, +012.
+func (s *Scanner) UtilMatchInteger() bool {
+	ss := *s
+	i := 0
+	if len(ss) > 0 && (ss[0] == '-' || ss[0] == '+') {
+		i++
+	}
+	n := i
+	for i < len(ss) && ss[i] >= '0' && ss[i] <= '9' {
+		i++
+	}
+	if i > n {
+		*s = ss[i:]
+		return true
+	}
+	return false
+}
+
+// UtilMatchFloat matches a decimal float with an
+// optional sign. It must have a fraction or an
+// exponent. Eg: 1.5, -1e3, 1.5E-3.
+func (s *Scanner) UtilMatchFloat() bool {
+	ss := *s
+	i := 0
+	if len(ss) > 0 && (ss[0] == '-' || ss[0] == '+') {
+		i++
+	}
+	n := i
+	for i < len(ss) && ss[i] >= '0' && ss[i] <= '9' {
+		i++
+	}
+	if i == n {
+		return false
+	}
+	float := false
+	// Fraction.
+	if i < len(ss) && ss[i] == '.' {
+		if i++; i == len(ss) || ss[i] < '0' || ss[i] > '9' {
+			return false
+		}
+		for i < len(ss) && ss[i] >= '0' && ss[i] <= '9' {
+			i++
+		}
+		float = true
+	}
+	// Exponent.
+	if i < len(ss) && (ss[i] == 'e' || ss[i] == 'E') {
+		if i++; i < len(ss) && (ss[i] == '+' || ss[i] == '-') {
+			i++
+		}
+		if i == len(ss) || ss[i] < '0' || ss[i] > '9' {
+			return false
+		}
+		for i < len(ss) && ss[i] >= '0' && ss[i] <= '9' {
+			i++
+		}
+		float = true
+	}
+	if float {
+		*s = ss[i:]
+	}
+	return float
+}
+
+// UtilMatchHex matches a hexadecimal integer
+// prefixed by 0x or 0X. Eg: 0xFF.
+func (s *Scanner) UtilMatchHex() bool {
+	return s.matchPrefixed('x', 16)
+}
+
+// UtilMatchOctal matches an octal integer
+// prefixed by 0o or 0O. Eg: 0o755.
+func (s *Scanner) UtilMatchOctal() bool {
+	return s.matchPrefixed('o', 8)
+}
+
+// UtilMatchBinary matches a binary integer
+// prefixed by 0b or 0B. Eg: 0b1010.
+func (s *Scanner) UtilMatchBinary() bool {
+	return s.matchPrefixed('b', 2)
+}
+
+func (s *Scanner) matchPrefixed(prefix byte, base int) bool {
+	ss := *s
+	if len(ss) > 2 && ss[0] == '0' && ss[1]|0x20 == prefix {
+		i := 2
+		for i < len(ss) && isDigit(ss[i], base) {
+			i++
+		}
+		if i > 2 {
+			*s = ss[i:]
+			return true
+		}
+	}
+	return false
+}
+
+// NumberFormat describes the numeric literals
+// accepted by UtilMatchNumberFormat.
+type NumberFormat struct {
+	// Sign accepts a leading '+' or '-'.
+	// Prefixed numbers never take a sign.
+	Sign bool
+	// Hex accepts 0x and 0X prefixed integers.
+	Hex bool
+	// Octal accepts 0o and 0O prefixed integers.
+	Octal bool
+	// Binary accepts 0b and 0B prefixed integers.
+	Binary bool
+	// LegacyOctal accepts integers with a leading
+	// zero as octal, like C's 0755.
+	LegacyOctal bool
+	// LeadingZeros accepts decimal numbers with
+	// leading zeros, like 007.
+	LeadingZeros bool
+	// Separator is a digit separator, like '_' in 1_000.
+	// It must be between two digits. Zero disables it.
+	Separator byte
+	// SeparatorAfterPrefix also accepts the separator
+	// right after a base prefix, like 0x_FF.
+	SeparatorAfterPrefix bool
+	// Float accepts a fraction and an exponent.
+	Float bool
+	// BareDot accepts floats without digits
+	// on one side of the dot, like .5 and 5.
+	BareDot bool
+	// HexFloat accepts hexadecimal floats, like 0x1.8p3.
+	HexFloat bool
+	// Inf is the spelling of infinity, like "inf".
+	// Empty disables it.
+	Inf string
+	// NaN is the spelling of not-a-number, like "nan".
+	// Empty disables it.
+	NaN string
+}
+
+// Number formats of some languages.
+// Suffixes like C's 10UL are not matched.
+var (
+	NumberGo = NumberFormat{
+		Hex: true, Octal: true, Binary: true, LegacyOctal: true,
+		Separator: '_', SeparatorAfterPrefix: true,
+		Float: true, BareDot: true, HexFloat: true,
+	}
+	NumberC = NumberFormat{
+		Hex: true, Binary: true, LegacyOctal: true,
+		Separator: '\'', Float: true, BareDot: true, HexFloat: true,
+	}
+	NumberJS = NumberFormat{
+		Hex: true, Octal: true, Binary: true,
+		Separator: '_', Float: true, BareDot: true,
+		Inf: "Infinity", NaN: "NaN",
+	}
+	NumberPython = NumberFormat{
+		Hex: true, Octal: true, Binary: true,
+		Separator: '_', SeparatorAfterPrefix: true,
+		Float: true, BareDot: true,
+	}
+	NumberTOML = NumberFormat{
+		Sign: true, Hex: true, Octal: true, Binary: true,
+		Separator: '_', Float: true,
+		Inf: "inf", NaN: "nan",
+	}
+)
+
+// UtilMatchNumberFormat matches a numeric literal given a format.
+func (s *Scanner) UtilMatchNumberFormat(f NumberFormat) bool {
+	ss := *s
+	i := 0
+	if f.Sign && len(ss) > 0 && (ss[0] == '+' || ss[0] == '-') {
+		i++
+	}
+	// The spellings are words, so "information" is not inf.
+	for _, w := range [...]string{f.Inf, f.NaN} {
+		if w != "" && ss[i:].Equal(w) {
+			if j := i + len(w); j == len(ss) || !isWordByte(ss[j]) {
+				*s = ss[j:]
+				return true
+			}
+		}
+	}
+	// Prefixed.
+	if i == 0 && len(ss) > 1 && ss[0] == '0' {
+		base := 0
+		switch ss[1] | 0x20 {
+		case 'x':
+			if f.Hex {
+				base = 16
+			}
+		case 'o':
+			if f.Octal {
+				base = 8
+			}
+		case 'b':
+			if f.Binary {
+				base = 2
+			}
+		}
+		if base != 0 {
+			n := f.digits(ss[2:], base, f.SeparatorAfterPrefix)
+			if n < 0 {
+				return false
+			}
+			i = 2 + n
+			if base == 16 && f.HexFloat {
+				m, ok := f.hexFloat(ss[i:], n > 0)
+				if !ok {
+					return false
+				}
+				i += m
+			}
+			if i == 2 {
+				return false
+			}
+			*s = ss[i:]
+			return true
+		}
+	}
+	// Integer.
+	ini := i
+	n := f.digits(ss[i:], 10, false)
+	if n < 0 {
+		return false
+	}
+	i += n
+	intg := ss[ini:i]
+	// Fraction.
+	float := false
+	if f.Float && i < len(ss) && ss[i] == '.' {
+		m := f.digits(ss[i+1:], 10, false)
+		if m < 0 {
+			return false
+		}
+		// Otherwise the dot is not part of the number,
+		// like in 1..2 or 1.String().
+		if (m > 0 && (n > 0 || f.BareDot)) || (m == 0 && n > 0 && f.BareDot) {
+			i += 1 + m
+			float = true
+		}
+	}
+	if n == 0 && !float {
+		return false
+	}
+	// Exponent. Without digits the exponent is not
+	// part of the number, like in 1em.
+	if f.Float && i < len(ss) && (ss[i] == 'e' || ss[i] == 'E') {
+		j := i + 1
+		if j < len(ss) && (ss[j] == '+' || ss[j] == '-') {
+			j++
+		}
+		m := f.digits(ss[j:], 10, false)
+		if m < 0 {
+			return false
+		}
+		if m > 0 {
+			i = j + m
+			float = true
+		}
+	}
+	// Leading zeros.
+	if len(intg) > 1 && intg[0] == '0' && !f.LeadingZeros {
+		if !f.LegacyOctal {
+			return false
+		}
+		if !float {
+			for k := 1; k < len(intg); k++ {
+				if intg[k] != f.Separator && !isDigit(intg[k], 8) {
+					return false
+				}
+			}
+		}
+	}
+	*s = ss[i:]
+	return true
+}
+
+// hexFloat matches the fraction and the mandatory exponent
+// of a hexadecimal float. The mantissa tells if there were
+// hex digits before it.
+func (f NumberFormat) hexFloat(ss Scanner, mantissa bool) (int, bool) {
+	i := 0
+	if i < len(ss) && ss[i] == '.' {
+		n := f.digits(ss[1:], 16, false)
+		if n < 0 || (n == 0 && !mantissa) {
+			return 0, false
+		}
+		i += 1 + n
+		mantissa = true
+	}
+	if i < len(ss) && (ss[i] == 'p' || ss[i] == 'P') && mantissa {
+		j := i + 1
+		if j < len(ss) && (ss[j] == '+' || ss[j] == '-') {
+			j++
+		}
+		n := f.digits(ss[j:], 10, false)
+		if n <= 0 {
+			return 0, false
+		}
+		return j + n, true
+	}
+	// A fraction requires an exponent.
+	return 0, i == 0
+}
+
+// digits returns the length of the digits in ss.
+// Separators must be between digits, or at the start
+// when lead is true. Returns -1 on misplaced separators.
+func (f NumberFormat) digits(ss Scanner, base int, lead bool) int {
+	i := 0
+	for i < len(ss) {
+		if isDigit(ss[i], base) {
+			i++
+			continue
+		}
+		if f.Separator != 0 && ss[i] == f.Separator {
+			if (i == 0 && !lead) || i+1 == len(ss) || !isDigit(ss[i+1], base) {
+				return -1
+			}
+			i++
+			continue
+		}
+		break
+	}
+	return i
+}
+
+// isWordByte tells if c can be part of a word, like
+// a letter, a digit, '_' or a byte of a non-ASCII rune.
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c|0x20 >= 'a' && c|0x20 <= 'z' || c >= 0x80
+}
+
+// isDigit tells if c is a digit in a base up to 16.
+func isDigit(c byte, base int) bool {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c-'0') < base
+	case c|0x20 >= 'a' && c|0x20 <= 'f':
+		return base == 16
+	}
+	return false
+}
+
+// #endregion Number
+
+// #region Parse
+
+// NumError records a failed conversion.
+type NumError struct {
+	Pos int    // Byte offset, from where the scan started, of the failure.
+	Num string // The input scanned up to and including the failure.
+	Err error  // Either strconv.ErrSyntax or strconv.ErrRange.
+}
+
+func (e *NumError) Error() string {
+	return "scanner: parsing " + strconv.Quote(e.Num) + " at " + strconv.Itoa(e.Pos) + ": " + e.Err.Error()
+}
+
+func (e *NumError) Unwrap() error {
+	return e.Err
+}
+
+func numError(ss Scanner, pos int, err error) *NumError {
+	end := pos + 1
+	if end > len(ss) {
+		end = len(ss)
+	}
+	return &NumError{Pos: pos, Num: ss[:end].String(), Err: err}
+}
+
+// UtilParseInt matches a decimal integer with an
+// optional sign and converts it to an int64.
+// The scanner does not move on error.
+func (s *Scanner) UtilParseInt() (int64, error) {
+	ss := *s
+	i := 0
+	neg := false
+	if len(ss) > 0 && (ss[0] == '-' || ss[0] == '+') {
+		neg = ss[0] == '-'
+		i++
+	}
+	limit := uint64(math.MaxInt64)
+	if neg {
+		limit++
+	}
+	v, n, err := parseUint(ss, i, limit)
+	if err != nil {
+		return 0, err
+	}
+	*s = ss[n:]
+	if neg {
+		return -int64(v), nil
+	}
+	return int64(v), nil
+}
+
+// UtilParseUint matches a decimal integer
+// and converts it to an uint64.
+// The scanner does not move on error.
+func (s *Scanner) UtilParseUint() (uint64, error) {
+	v, n, err := parseUint(*s, 0, math.MaxUint64)
+	if err != nil {
+		return 0, err
+	}
+	*s = (*s)[n:]
+	return v, nil
+}
+
+// parseUint converts the digits of ss starting at i.
+// Returns the value and the end of the digits.
+func parseUint(ss Scanner, i int, limit uint64) (uint64, int, error) {
+	if i == len(ss) || ss[i] < '0' || ss[i] > '9' {
+		return 0, 0, numError(ss, i, strconv.ErrSyntax)
+	}
+	var v uint64
+	for ; i < len(ss) && ss[i] >= '0' && ss[i] <= '9'; i++ {
+		d := uint64(ss[i] - '0')
+		if v > (limit-d)/10 {
+			return 0, 0, numError(ss, i, strconv.ErrRange)
+		}
+		v = v*10 + d
+	}
+	return v, i, nil
+}
+
+// UtilParseFloat matches a JSON number
+// and converts it to a float64.
+// The scanner does not move on error.
+func (s *Scanner) UtilParseFloat() (float64, error) {
+	ss := *s
+	d, err := scanDecimal(ss)
+	if err != nil {
+		return 0, err
+	}
+	// Fast path: the mantissa and the power
+	// of ten are exact in a float64.
+	if m, ok := d.mantissa(); ok && m < 1<<53 {
+		if e := d.exp10(); e >= -22 && e <= 22 {
+			f := float64(m)
+			if e < 0 {
+				f /= pow10[-e]
+			} else {
+				f *= pow10[e]
+			}
+			if d.neg {
+				f = -f
+			}
+			*s = ss[d.end:]
+			return f, nil
+		}
+	}
+	f, err := strconv.ParseFloat(ss[:d.end].String(), 64)
+	if err != nil {
+		return 0, numError(ss, d.end-1, strconv.ErrRange)
+	}
+	*s = ss[d.end:]
+	return f, nil
+}
+
+// UtilParseBigInt matches a decimal integer with an
+// optional sign and converts it to a big.Int.
+// The scanner does not move on error.
+func (s *Scanner) UtilParseBigInt() (*big.Int, error) {
+	ss := *s
+	i := 0
+	if len(ss) > 0 && (ss[0] == '-' || ss[0] == '+') {
+		i++
+	}
+	n := i
+	for n < len(ss) && ss[n] >= '0' && ss[n] <= '9' {
+		n++
+	}
+	if n == i {
+		return nil, numError(ss, i, strconv.ErrSyntax)
+	}
+	v := bigDigits(new(big.Int), ss[i:n])
+	if ss[0] == '-' {
+		v.Neg(v)
+	}
+	*s = ss[n:]
+	return v, nil
+}
+
+// UtilParseBigRat matches a JSON number and converts
+// it to a big.Rat. The decimal value is kept exact.
+// The scanner does not move on error.
+func (s *Scanner) UtilParseBigRat() (*big.Rat, error) {
+	ss := *s
+	d, err := scanDecimal(ss)
+	if err != nil {
+		return nil, err
+	}
+	r, err := d.rat(ss)
+	if err != nil {
+		return nil, err
+	}
+	*s = ss[d.end:]
+	return r, nil
+}
+
+// UtilParseBigFloat matches a JSON number and converts
+// it to a big.Float rounded to prec bits. A zero prec
+// uses the precision needed by the number.
+// The scanner does not move on error.
+func (s *Scanner) UtilParseBigFloat(prec uint) (*big.Float, error) {
+	ss := *s
+	d, err := scanDecimal(ss)
+	if err != nil {
+		return nil, err
+	}
+	r, err := d.rat(ss)
+	if err != nil {
+		return nil, err
+	}
+	*s = ss[d.end:]
+	return new(big.Float).SetPrec(prec).SetRat(r), nil
+}
+
+// UtilParseHex matches exactly n hex digits, up to 8,
+// and returns their value. It returns false if there
+// are less than n digits.
+// The scanner does not move on error.
+func (s *Scanner) UtilParseHex(n int) (uint32, bool) {
+	ss := *s
+	if n <= 0 || n > 8 || len(ss) < n {
+		return 0, false
+	}
+	var v uint32
+	for i := 0; i < n; i++ {
+		c := ss[i]
+		switch {
+		case c >= '0' && c <= '9':
+			v = v<<4 | uint32(c-'0')
+		case c|0x20 >= 'a' && c|0x20 <= 'f':
+			v = v<<4 | uint32(c|0x20-'a'+10)
+		default:
+			return 0, false
+		}
+	}
+	*s = ss[n:]
+	return v, true
+}
+
+// UtilParseHexRune matches exactly n hex digits, up to
+// 8, like the 00E9 of \u00E9, and returns the code point.
+// It returns false if there are less than n digits or
+// the code point is a surrogate or above U+10FFFF.
+// The scanner does not move on error.
+func (s *Scanner) UtilParseHexRune(n int) (rune, bool) {
+	ss := *s
+	v, ok := ss.UtilParseHex(n)
+	if !ok || !utf8.ValidRune(rune(v)) {
+		return 0, false
+	}
+	*s = ss
+	return rune(v), true
+}
+
+// maxExp10 limits the exponent of big numbers
+// to keep huge exponents like 1e999999999
+// from exhausting the memory.
+const maxExp10 = 100000
+
+var pow10 = [...]float64{
+	1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10,
+	1e11, 1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18, 1e19, 1e20,
+	1e21, 1e22,
+}
+
+// decimal is a scanned JSON number.
+type decimal struct {
+	neg  bool
+	intg Scanner // Integer digits.
+	frac Scanner // Fraction digits.
+	exp  int     // Exponent, saturated at maxExp10.
+	end  int     // Length of the number.
+}
+
+// scanDecimal scans a JSON number like UtilMatchNumber does.
+func scanDecimal(ss Scanner) (d decimal, err error) {
+	i := 0
+	if i < len(ss) && ss[i] == '-' {
+		d.neg = true
+		i++
+	}
+	// Int.
+	n := i
+	if i < len(ss) && ss[i] == '0' {
+		i++
+	} else {
+		for i < len(ss) && ss[i] >= '0' && ss[i] <= '9' {
+			i++
+		}
+	}
+	if i == n {
+		return d, numError(ss, i, strconv.ErrSyntax)
+	}
+	d.intg = ss[n:i]
+	if i < len(ss) && ss[i] >= '0' && ss[i] <= '9' {
+		return d, numError(ss, i, strconv.ErrSyntax)
+	}
+	// Fraction.
+	if i < len(ss) && ss[i] == '.' {
+		i++
+		n = i
+		for i < len(ss) && ss[i] >= '0' && ss[i] <= '9' {
+			i++
+		}
+		if i == n {
+			return d, numError(ss, i, strconv.ErrSyntax)
+		}
+		d.frac = ss[n:i]
+	}
+	// Exponent.
+	if i < len(ss) && (ss[i] == 'e' || ss[i] == 'E') {
+		i++
+		neg := false
+		if i < len(ss) && (ss[i] == '+' || ss[i] == '-') {
+			neg = ss[i] == '-'
+			i++
+		}
+		n = i
+		for ; i < len(ss) && ss[i] >= '0' && ss[i] <= '9'; i++ {
+			if d.exp < maxExp10 {
+				d.exp = d.exp*10 + int(ss[i]-'0')
+			}
+		}
+		if i == n {
+			return d, numError(ss, i, strconv.ErrSyntax)
+		}
+		if d.exp > maxExp10 {
+			d.exp = maxExp10
+		}
+		if neg {
+			d.exp = -d.exp
+		}
+	}
+	d.end = i
+	return d, nil
+}
+
+// mantissa returns the integer and fraction digits
+// as an integer, if it fits in an uint64.
+func (d decimal) mantissa() (uint64, bool) {
+	if len(d.intg)+len(d.frac) > 19 {
+		return 0, false
+	}
+	var m uint64
+	for i := 0; i < len(d.intg); i++ {
+		m = m*10 + uint64(d.intg[i]-'0')
+	}
+	for i := 0; i < len(d.frac); i++ {
+		m = m*10 + uint64(d.frac[i]-'0')
+	}
+	return m, true
+}
+
+// exp10 returns the power of ten to apply to the mantissa.
+func (d decimal) exp10() int {
+	return d.exp - len(d.frac)
+}
+
+func (d decimal) rat(ss Scanner) (*big.Rat, error) {
+	if d.exp == maxExp10 || d.exp == -maxExp10 {
+		return nil, numError(ss, d.end-1, strconv.ErrRange)
+	}
+	m := bigDigits(new(big.Int), d.intg)
+	m = bigDigits(m, d.frac)
+	if d.neg {
+		m.Neg(m)
+	}
+	r := new(big.Rat).SetInt(m)
+	if e := d.exp10(); e != 0 {
+		p := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(e))), nil)
+		if e > 0 {
+			r.Mul(r, new(big.Rat).SetInt(p))
+		} else {
+			r.Quo(r, new(big.Rat).SetInt(p))
+		}
+	}
+	return r, nil
+}
+
+// bigDigits appends the decimal digits of ss to v.
+// Digits are added in chunks that fit in an uint64.
+func bigDigits(v *big.Int, ss Scanner) *big.Int {
+	var chunk big.Int
+	for len(ss) > 0 {
+		n := len(ss)
+		if n > 19 {
+			n = 19
+		}
+		var c uint64
+		for i := 0; i < n; i++ {
+			c = c*10 + uint64(ss[i]-'0')
+		}
+		v.Mul(v, chunk.SetUint64(pow10u[n]))
+		v.Add(v, chunk.SetUint64(c))
+		ss = ss[n:]
+	}
+	return v
+}
+
+var pow10u = [...]uint64{
+	1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10,
+	1e11, 1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18, 1e19,
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// #endregion Parse
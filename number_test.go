@@ -0,0 +1,519 @@
+package scanner
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"testing"
+)
+
+// #region Number
+
+func TestScannerUtilMatchInteger(t *testing.T) {
+	tt := []struct {
+		give string
+		then bool
+		exp  string
+	}{
+		{give: `123`, then: true, exp: `123`},
+		{give: `-123`, then: true, exp: `-123`},
+		{give: `+123`, then: true, exp: `+123`},
+		{give: `007`, then: true, exp: `007`},
+		{give: `12.5`, then: true, exp: `12`},
+		{give: `-`, then: false, exp: ``},
+		{give: `a`, then: false, exp: ``},
+		{give: ``, then: false, exp: ``},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		m := s.Mark()
+		assertEqual(t, tc.then, s.UtilMatchInteger(), tc)
+		assertEqual(t, tc.exp, s.Token(m), tc)
+	}
+}
+
+func BenchmarkScannerUtilMatchInteger(b *testing.B) {
+	x := Scanner(`-123`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilMatchInteger()
+	}
+}
+
+func TestScannerUtilMatchFloat(t *testing.T) {
+	tt := []struct {
+		give string
+		then bool
+		exp  string
+	}{
+		{give: `1.5`, then: true, exp: `1.5`},
+		{give: `-1.5`, then: true, exp: `-1.5`},
+		{give: `+1.5e3`, then: true, exp: `+1.5e3`},
+		{give: `1e3`, then: true, exp: `1e3`},
+		{give: `1E-3`, then: true, exp: `1E-3`},
+		{give: `1.5x`, then: true, exp: `1.5`},
+		{give: `1`, then: false, exp: ``},
+		{give: `1.`, then: false, exp: ``},
+		{give: `.5`, then: false, exp: ``},
+		{give: `1e`, then: false, exp: ``},
+		{give: `1e+`, then: false, exp: ``},
+		{give: ``, then: false, exp: ``},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		m := s.Mark()
+		assertEqual(t, tc.then, s.UtilMatchFloat(), tc)
+		assertEqual(t, tc.exp, s.Token(m), tc)
+	}
+}
+
+func BenchmarkScannerUtilMatchFloat(b *testing.B) {
+	x := Scanner(`-1.5e3`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilMatchFloat()
+	}
+}
+
+func TestScannerUtilMatchHex(t *testing.T) {
+	tt := []struct {
+		give string
+		then bool
+		exp  string
+	}{
+		{give: `0xFF`, then: true, exp: `0xFF`},
+		{give: `0Xaf09`, then: true, exp: `0Xaf09`},
+		{give: `0xFG`, then: true, exp: `0xF`},
+		{give: `0x`, then: false, exp: ``},
+		{give: `0xG`, then: false, exp: ``},
+		{give: `FF`, then: false, exp: ``},
+		{give: ``, then: false, exp: ``},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		m := s.Mark()
+		assertEqual(t, tc.then, s.UtilMatchHex(), tc)
+		assertEqual(t, tc.exp, s.Token(m), tc)
+	}
+}
+
+func BenchmarkScannerUtilMatchHex(b *testing.B) {
+	x := Scanner(`0xFF`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilMatchHex()
+	}
+}
+
+func TestScannerUtilMatchOctal(t *testing.T) {
+	tt := []struct {
+		give string
+		then bool
+		exp  string
+	}{
+		{give: `0o755`, then: true, exp: `0o755`},
+		{give: `0O17`, then: true, exp: `0O17`},
+		{give: `0o78`, then: true, exp: `0o7`},
+		{give: `0o8`, then: false, exp: ``},
+		{give: `0755`, then: false, exp: ``},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		m := s.Mark()
+		assertEqual(t, tc.then, s.UtilMatchOctal(), tc)
+		assertEqual(t, tc.exp, s.Token(m), tc)
+	}
+}
+
+func BenchmarkScannerUtilMatchOctal(b *testing.B) {
+	x := Scanner(`0o755`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilMatchOctal()
+	}
+}
+
+func TestScannerUtilMatchBinary(t *testing.T) {
+	tt := []struct {
+		give string
+		then bool
+		exp  string
+	}{
+		{give: `0b1010`, then: true, exp: `0b1010`},
+		{give: `0B1`, then: true, exp: `0B1`},
+		{give: `0b12`, then: true, exp: `0b1`},
+		{give: `0b2`, then: false, exp: ``},
+		{give: `0b`, then: false, exp: ``},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		m := s.Mark()
+		assertEqual(t, tc.then, s.UtilMatchBinary(), tc)
+		assertEqual(t, tc.exp, s.Token(m), tc)
+	}
+}
+
+func BenchmarkScannerUtilMatchBinary(b *testing.B) {
+	x := Scanner(`0b1010`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilMatchBinary()
+	}
+}
+
+func TestScannerUtilMatchNumberFormat(t *testing.T) {
+	tt := []struct {
+		give string
+		when NumberFormat
+		then bool
+		exp  string
+	}{
+		// Go.
+		{give: `123`, when: NumberGo, then: true, exp: `123`},
+		{give: `1_000`, when: NumberGo, then: true, exp: `1_000`},
+		{give: `1__000`, when: NumberGo, then: false, exp: ``},
+		{give: `1_`, when: NumberGo, then: false, exp: ``},
+		{give: `_1`, when: NumberGo, then: false, exp: ``},
+		{give: `0x_FF`, when: NumberGo, then: true, exp: `0x_FF`},
+		{give: `0o17`, when: NumberGo, then: true, exp: `0o17`},
+		{give: `0b1_0`, when: NumberGo, then: true, exp: `0b1_0`},
+		{give: `0755`, when: NumberGo, then: true, exp: `0755`},
+		{give: `0789`, when: NumberGo, then: false, exp: ``},
+		{give: `0789.5`, when: NumberGo, then: true, exp: `0789.5`},
+		{give: `1.5e3`, when: NumberGo, then: true, exp: `1.5e3`},
+		{give: `.5`, when: NumberGo, then: true, exp: `.5`},
+		{give: `5.`, when: NumberGo, then: true, exp: `5.`},
+		{give: `.`, when: NumberGo, then: false, exp: ``},
+		{give: `0x1.8p3`, when: NumberGo, then: true, exp: `0x1.8p3`},
+		{give: `0x1p-2`, when: NumberGo, then: true, exp: `0x1p-2`},
+		{give: `0x.8p1`, when: NumberGo, then: true, exp: `0x.8p1`},
+		{give: `0x1.8`, when: NumberGo, then: false, exp: ``},
+		{give: `0xp1`, when: NumberGo, then: false, exp: ``},
+		{give: `0x`, when: NumberGo, then: false, exp: ``},
+		{give: `-1`, when: NumberGo, then: false, exp: ``},
+		{give: `1em`, when: NumberGo, then: true, exp: `1`},
+		// C.
+		{give: `1'000`, when: NumberC, then: true, exp: `1'000`},
+		{give: `0o17`, when: NumberC, then: true, exp: `0`},
+		// JS.
+		{give: `Infinity`, when: NumberJS, then: true, exp: `Infinity`},
+		{give: `NaN`, when: NumberJS, then: true, exp: `NaN`},
+		{give: `Infinity)`, when: NumberJS, then: true, exp: `Infinity`},
+		{give: `Infinityx`, when: NumberJS, then: false, exp: ``},
+		{give: `NaN_`, when: NumberJS, then: false, exp: ``},
+		{give: `0x_FF`, when: NumberJS, then: false, exp: ``},
+		{give: `0755`, when: NumberJS, then: false, exp: ``},
+		{give: `0`, when: NumberJS, then: true, exp: `0`},
+		// Python.
+		{give: `0x_FF`, when: NumberPython, then: true, exp: `0x_FF`},
+		{give: `1_000.000_1`, when: NumberPython, then: true, exp: `1_000.000_1`},
+		// TOML.
+		{give: `+1_000`, when: NumberTOML, then: true, exp: `+1_000`},
+		{give: `-inf`, when: NumberTOML, then: true, exp: `-inf`},
+		{give: `+nan`, when: NumberTOML, then: true, exp: `+nan`},
+		{give: `inf,`, when: NumberTOML, then: true, exp: `inf`},
+		{give: `information`, when: NumberTOML, then: false, exp: ``},
+		{give: `nanny`, when: NumberTOML, then: false, exp: ``},
+		{give: `-inf2`, when: NumberTOML, then: false, exp: ``},
+		{give: `-0xFF`, when: NumberTOML, then: true, exp: `-0`},
+		{give: `0xdead_beef`, when: NumberTOML, then: true, exp: `0xdead_beef`},
+		{give: `1.`, when: NumberTOML, then: true, exp: `1`},
+		{give: `.5`, when: NumberTOML, then: false, exp: ``},
+		{give: `6.626e-34`, when: NumberTOML, then: true, exp: `6.626e-34`},
+		{give: `07`, when: NumberTOML, then: false, exp: ``},
+		// Custom.
+		{give: `007`, when: NumberFormat{LeadingZeros: true}, then: true, exp: `007`},
+		{give: `1.5`, when: NumberFormat{}, then: true, exp: `1`},
+		{give: ``, when: NumberFormat{}, then: false, exp: ``},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		m := s.Mark()
+		assertEqual(t, tc.then, s.UtilMatchNumberFormat(tc.when), tc.give)
+		assertEqual(t, tc.exp, s.Token(m), tc.give)
+	}
+}
+
+func BenchmarkScannerUtilMatchNumberFormat(b *testing.B) {
+	x := Scanner(`1_000.5e3`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilMatchNumberFormat(NumberGo)
+	}
+}
+
+// #endregion Number
+
+// #region Parse
+
+func TestScannerUtilParseInt(t *testing.T) {
+	tt := []struct {
+		give string
+		then int64
+		err  error
+		pos  int
+		exp  string
+	}{
+		{give: `123`, then: 123, exp: ``},
+		{give: `-123,`, then: -123, exp: `,`},
+		{give: `+7`, then: 7, exp: ``},
+		{give: `9223372036854775807`, then: math.MaxInt64, exp: ``},
+		{give: `-9223372036854775808`, then: math.MinInt64, exp: ``},
+		{give: `9223372036854775808`, err: strconv.ErrRange, pos: 18, exp: `9223372036854775808`},
+		{give: `-9223372036854775809`, err: strconv.ErrRange, pos: 19, exp: `-9223372036854775809`},
+		{give: `-x`, err: strconv.ErrSyntax, pos: 1, exp: `-x`},
+		{give: ``, err: strconv.ErrSyntax, pos: 0, exp: ``},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		v, err := s.UtilParseInt()
+		assertEqual(t, tc.then, v, tc)
+		assertNumError(t, tc.err, tc.pos, err, tc)
+		assertEqual(t, tc.exp, s.String(), tc)
+	}
+}
+
+func BenchmarkScannerUtilParseInt(b *testing.B) {
+	x := Scanner(`-123456789`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilParseInt()
+	}
+}
+
+func TestScannerUtilParseUint(t *testing.T) {
+	tt := []struct {
+		give string
+		then uint64
+		err  error
+		pos  int
+		exp  string
+	}{
+		{give: `123`, then: 123, exp: ``},
+		{give: `18446744073709551615`, then: math.MaxUint64, exp: ``},
+		{give: `18446744073709551616`, err: strconv.ErrRange, pos: 19, exp: `18446744073709551616`},
+		{give: `-1`, err: strconv.ErrSyntax, pos: 0, exp: `-1`},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		v, err := s.UtilParseUint()
+		assertEqual(t, tc.then, v, tc)
+		assertNumError(t, tc.err, tc.pos, err, tc)
+		assertEqual(t, tc.exp, s.String(), tc)
+	}
+}
+
+func BenchmarkScannerUtilParseUint(b *testing.B) {
+	x := Scanner(`123456789`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilParseUint()
+	}
+}
+
+func TestScannerUtilParseFloat(t *testing.T) {
+	tt := []struct {
+		give string
+		then float64
+		err  error
+		pos  int
+		exp  string
+	}{
+		{give: `0`, then: 0, exp: ``},
+		{give: `1.5`, then: 1.5, exp: ``},
+		{give: `-12.01e-12]`, then: -12.01e-12, exp: `]`},
+		{give: `0.1`, then: 0.1, exp: ``},
+		{give: `1E22`, then: 1e22, exp: ``},
+		{give: `1e23`, then: 1e23, exp: ``},
+		{give: `123456789012345678901234567890`, then: 123456789012345678901234567890, exp: ``},
+		{give: `2.2250738585072014e-308`, then: 2.2250738585072014e-308, exp: ``},
+		{give: `1e400`, err: strconv.ErrRange, pos: 4, exp: `1e400`},
+		{give: `-1.5e400,`, err: strconv.ErrRange, pos: 7, exp: `-1.5e400,`},
+		{give: `01`, err: strconv.ErrSyntax, pos: 1, exp: `01`},
+		{give: `1.e1`, err: strconv.ErrSyntax, pos: 2, exp: `1.e1`},
+		{give: `1e+`, err: strconv.ErrSyntax, pos: 3, exp: `1e+`},
+		{give: `+1`, err: strconv.ErrSyntax, pos: 0, exp: `+1`},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		v, err := s.UtilParseFloat()
+		assertEqual(t, tc.then, v, tc)
+		assertNumError(t, tc.err, tc.pos, err, tc)
+		assertEqual(t, tc.exp, s.String(), tc)
+	}
+}
+
+func BenchmarkScannerUtilParseFloat(b *testing.B) {
+	x := Scanner(`-12.01e-12`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilParseFloat()
+	}
+}
+
+func TestScannerUtilParseBigInt(t *testing.T) {
+	tt := []struct {
+		give string
+		then string
+		err  error
+		exp  string
+	}{
+		{give: `123456789012345678901234567890`, then: `123456789012345678901234567890`, exp: ``},
+		{give: `-1 `, then: `-1`, exp: ` `},
+		{give: `+`, err: strconv.ErrSyntax, exp: `+`},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		v, err := s.UtilParseBigInt()
+		if tc.err == nil {
+			assertEqual(t, tc.then, v.String(), tc)
+		}
+		assertEqual(t, true, errors.Is(err, tc.err), tc)
+		assertEqual(t, tc.exp, s.String(), tc)
+	}
+}
+
+func BenchmarkScannerUtilParseBigInt(b *testing.B) {
+	x := Scanner(`123456789012345678901234567890`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilParseBigInt()
+	}
+}
+
+func TestScannerUtilParseBigRat(t *testing.T) {
+	tt := []struct {
+		give string
+		then string
+		err  error
+		exp  string
+	}{
+		{give: `0.1`, then: `1/10`, exp: ``},
+		{give: `-12.50`, then: `-25/2`, exp: ``},
+		{give: `1.5e3`, then: `1500/1`, exp: ``},
+		{give: `1e-2,`, then: `1/100`, exp: `,`},
+		{give: `12345678901234567890.12345678901234567890`, then: `123456789012345678901234567890123456789/10000000000000000000`, exp: ``},
+		{give: `1e999999999`, err: strconv.ErrRange, exp: `1e999999999`},
+		{give: `.1`, err: strconv.ErrSyntax, exp: `.1`},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		v, err := s.UtilParseBigRat()
+		if tc.err == nil {
+			assertEqual(t, tc.then, v.String(), tc)
+		}
+		assertEqual(t, true, errors.Is(err, tc.err), tc)
+		assertEqual(t, tc.exp, s.String(), tc)
+	}
+}
+
+func BenchmarkScannerUtilParseBigRat(b *testing.B) {
+	x := Scanner(`-12.01e-12`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilParseBigRat()
+	}
+}
+
+func TestScannerUtilParseBigFloat(t *testing.T) {
+	s := Scanner(`0.1`)
+	v, err := s.UtilParseBigFloat(200)
+	exp, _ := new(big.Float).SetPrec(200).SetString("0.1")
+	assertEqual(t, nil, err)
+	assertEqual(t, 0, v.Cmp(exp))
+	assertEqual(t, uint(200), v.Prec())
+	assertEqual(t, ``, s.String())
+}
+
+func BenchmarkScannerUtilParseBigFloat(b *testing.B) {
+	x := Scanner(`-12.01e-12`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilParseBigFloat(64)
+	}
+}
+
+func TestScannerUtilParseHex(t *testing.T) {
+	tt := []struct {
+		give string
+		when int
+		then uint32
+		ok   bool
+		exp  string
+	}{
+		{give: `D83Dx`, when: 4, then: 0xD83D, ok: true, exp: `x`},
+		{give: `FFFFFFFF`, when: 8, then: 0xFFFFFFFF, ok: true, exp: ``},
+		{give: `aB`, when: 2, then: 0xAB, ok: true, exp: ``},
+		{give: `1g`, when: 2, exp: `1g`},
+		{give: `1`, when: 2, exp: `1`},
+		{give: `123456789`, when: 9, exp: `123456789`},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		v, ok := s.UtilParseHex(tc.when)
+		assertEqual(t, tc.then, v, tc.give)
+		assertEqual(t, tc.ok, ok, tc.give)
+		assertEqual(t, tc.exp, s.String(), tc.give)
+	}
+}
+
+func BenchmarkScannerUtilParseHex(b *testing.B) {
+	x := Scanner(`0001F600`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilParseHex(8)
+	}
+}
+
+func TestScannerUtilParseHexRune(t *testing.T) {
+	tt := []struct {
+		give string
+		when int
+		then rune
+		ok   bool
+		exp  string
+	}{
+		{give: `00e9x`, when: 4, then: 'é', ok: true, exp: `x`},
+		{give: `0001F600`, when: 8, then: '😀', ok: true, exp: ``},
+		{give: `41`, when: 2, then: 'A', ok: true, exp: ``},
+		{give: `00E`, when: 4, exp: `00E`},
+		{give: `00eg`, when: 4, exp: `00eg`},
+		{give: `+0e9`, when: 4, exp: `+0e9`},
+		{give: `D800`, when: 4, exp: `D800`},
+		{give: `00110000`, when: 8, exp: `00110000`},
+		{give: `FFFFFFFF`, when: 8, exp: `FFFFFFFF`},
+		{give: `1`, when: 0, exp: `1`},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		v, ok := s.UtilParseHexRune(tc.when)
+		assertEqual(t, tc.then, v, tc.give)
+		assertEqual(t, tc.ok, ok, tc.give)
+		assertEqual(t, tc.exp, s.String(), tc.give)
+	}
+}
+
+func BenchmarkScannerUtilParseHexRune(b *testing.B) {
+	x := Scanner(`0001F600`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilParseHexRune(8)
+	}
+}
+
+// #endregion Parse
+
+func assertNumError(t *testing.T, exp error, pos int, got error, msgs ...any) {
+	t.Helper()
+	if exp == nil {
+		assertEqual(t, nil, got, msgs...)
+		return
+	}
+	var e *NumError
+	if !errors.As(got, &e) {
+		t.Fatalf("\nExp NumError\nGot:\n%v", got)
+	}
+	assertEqual(t, exp, e.Err, msgs...)
+	assertEqual(t, pos, e.Pos, msgs...)
+}
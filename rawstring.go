@@ -0,0 +1,186 @@
+package scanner
+
+import "strings"
+
+// #region RawString
+
+// UtilMatchRustRawString matches a Rust raw string,
+// like r"a" or r#"a "quoted" b"#. The number of '#'
+// chooses the terminator.
+func (s *Scanner) UtilMatchRustRawString() bool {
+	ss := *s
+	if !ss.MatchByte('r') {
+		return false
+	}
+	m := ss
+	ss.MatchWhileByteBy(func(c byte) bool { return c == '#' })
+	hashes := ss.Token(m)
+	if !ss.MatchByte('"') {
+		return false
+	}
+	term := `"` + hashes
+	if ss.MatchUntil(term) {
+		*s = ss[len(term):]
+		return true
+	}
+	return false
+}
+
+// UtilMatchCppRawString matches a C++ raw string,
+// like R"(a)" or R"x(a )" b)x". The delimiter
+// between the quote and the parenthesis chooses
+// the terminator.
+func (s *Scanner) UtilMatchCppRawString() bool {
+	ss := *s
+	if !ss.Match(`R"`) {
+		return false
+	}
+	m := ss
+	// The delimiter has up to 16 chars and can't have
+	// parentheses, backslashes, spaces or quotes.
+	ss.MatchWhileByteBy(func(c byte) bool {
+		return c > ' ' && c != '(' && c != ')' && c != '\\' && c != '"'
+	})
+	delim := ss.Token(m)
+	if len(delim) > 16 || !ss.MatchByte('(') {
+		return false
+	}
+	term := ")" + delim + `"`
+	if ss.MatchUntil(term) {
+		*s = ss[len(term):]
+		return true
+	}
+	return false
+}
+
+// Heredoc is a scanned here-document.
+type Heredoc struct {
+	// Delim is the terminator word, unquoted.
+	Delim string
+	// Quoted tells if the delimiter was quoted,
+	// like <<'EOF', usually meaning no expansion.
+	Quoted bool
+	// Strip tells if it is a shell <<- heredoc, whose
+	// terminator and body lines take leading tabs.
+	Strip bool
+	// Indent tells if it is a Ruby <<~ or a PHP <<<
+	// heredoc, whose terminator can be indented and
+	// whose body loses the common indentation.
+	Indent bool
+	// Body is the raw text between the opening
+	// line and the terminator line.
+	Body string
+}
+
+// Text returns the heredoc body, with the
+// indentation removed per Strip and Indent.
+func (h Heredoc) Text() string {
+	if !h.Strip && !h.Indent {
+		return h.Body
+	}
+	// Common indentation.
+	n := 0
+	if h.Indent {
+		n = -1
+		for s := Scanner(h.Body); s.More(); {
+			m := s
+			s.MatchWhileByteBy(isIndent)
+			if c := s.Curr(); c != '\n' && c != '\r' && s.More() {
+				if k := len(m) - len(s); n < 0 || k < n {
+					n = k
+				}
+			}
+			s.skipLine()
+		}
+	}
+	var b strings.Builder
+	for s := Scanner(h.Body); s.More(); {
+		if h.Strip {
+			s.MatchWhileByteBy(func(c byte) bool { return c == '\t' })
+		}
+		for i := 0; i < n && isIndent(s.Curr()); i++ {
+			s.Next()
+		}
+		m := s
+		s.skipLine()
+		b.WriteString(s.Token(m))
+	}
+	return b.String()
+}
+
+// UtilMatchHeredocStart matches a heredoc operator, like
+// <<EOF, <<-EOF, <<~EOF, <<<EOF, <<'EOF' or <<"EOF". The
+// body starts at the next line, so the caller scans the rest
+// of the current line and then calls UtilMatchHeredocBody.
+func (s *Scanner) UtilMatchHeredocStart(h *Heredoc) bool {
+	ss := *s
+	if !ss.Match("<<") {
+		return false
+	}
+	*h = Heredoc{}
+	h.Strip = ss.MatchByte('-')
+	h.Indent = !h.Strip && (ss.MatchByte('~') || ss.MatchByte('<'))
+	if q := ss.Curr(); q == '\'' || q == '"' {
+		ss.Next()
+		m := ss
+		if !ss.MatchUntilByte(q) {
+			return false
+		}
+		h.Delim, h.Quoted = ss.Token(m), true
+		ss.Next()
+	} else {
+		h.Delim = ss.TokenByteBy(isIdentByte)
+	}
+	if h.Delim == "" {
+		return false
+	}
+	*s = ss
+	return true
+}
+
+// UtilMatchHeredocBody matches a heredoc body up to and
+// including the terminator line. The scanner must be at
+// the start of the line after the heredoc operator.
+func (s *Scanner) UtilMatchHeredocBody(h *Heredoc) bool {
+	ss := *s
+	for m := ss; ; {
+		line := ss
+		switch {
+		case h.Strip:
+			line.MatchWhileByteBy(func(c byte) bool { return c == '\t' })
+		case h.Indent:
+			line.MatchWhileByteBy(isIndent)
+		}
+		if line.Match(h.Delim) {
+			// PHP allows code after the terminator.
+			if line.Match("\r\n") || line.MatchByte('\n') || !line.More() || h.Indent && !isIdentByte(line.Curr()) {
+				h.Body = ss.Token(m)
+				*s = line
+				return true
+			}
+		}
+		if !ss.MatchUntilByte('\n') {
+			return false
+		}
+		ss.Next()
+	}
+}
+
+// skipLine skips to the start of the next line.
+func (s *Scanner) skipLine() {
+	if s.MatchUntilByte('\n') {
+		s.Next()
+	} else {
+		*s = (*s)[len(*s):]
+	}
+}
+
+func isIndent(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c|0x20 >= 'a' && c|0x20 <= 'z' || c >= 0x80
+}
+
+// #endregion RawString
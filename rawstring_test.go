@@ -0,0 +1,137 @@
+package scanner
+
+import "testing"
+
+// #region RawString
+
+func TestScannerUtilMatchRustRawString(t *testing.T) {
+	tt := []struct {
+		give string
+		then bool
+		exp  string
+	}{
+		{give: `r"a\n" x`, then: true, exp: `r"a\n"`},
+		{give: `r#"a "b" c"# x`, then: true, exp: `r#"a "b" c"#`},
+		{give: `r##"a "# b"## x`, then: true, exp: `r##"a "# b"##`},
+		{give: `r#"a"`, then: false, exp: ``},
+		{give: `r#a`, then: false, exp: ``},
+		{give: `"a"`, then: false, exp: ``},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		m := s.Mark()
+		assertEqual(t, tc.then, s.UtilMatchRustRawString(), tc)
+		assertEqual(t, tc.exp, s.Token(m), tc)
+	}
+}
+
+func BenchmarkScannerUtilMatchRustRawString(b *testing.B) {
+	x := Scanner(`r#"a "b" c"#`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilMatchRustRawString()
+	}
+}
+
+func TestScannerUtilMatchCppRawString(t *testing.T) {
+	tt := []struct {
+		give string
+		then bool
+		exp  string
+	}{
+		{give: `R"(a\n)" x`, then: true, exp: `R"(a\n)"`},
+		{give: `R"x(a )" b)x" c`, then: true, exp: `R"x(a )" b)x"`},
+		{give: `R"x(a)"`, then: false, exp: ``},
+		{give: `R"x y(a)x y"`, then: false, exp: ``},
+		{give: `R"12345678901234567(a)12345678901234567"`, then: false, exp: ``},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		m := s.Mark()
+		assertEqual(t, tc.then, s.UtilMatchCppRawString(), tc)
+		assertEqual(t, tc.exp, s.Token(m), tc)
+	}
+}
+
+func BenchmarkScannerUtilMatchCppRawString(b *testing.B) {
+	x := Scanner(`R"x(a )" b)x"`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.UtilMatchCppRawString()
+	}
+}
+
+func TestScannerUtilMatchHeredoc(t *testing.T) {
+	tt := []struct {
+		give  string
+		delim string
+		quote bool
+		body  string
+		text  string
+		exp   string
+	}{
+		{
+			give:  "cat <<EOF\n$a\n  EOF\nEOF\necho",
+			delim: "EOF", body: "$a\n  EOF\n", text: "$a\n  EOF\n", exp: "echo",
+		},
+		{
+			give:  "cat <<'EOF' | x\n$a\nEOF",
+			delim: "EOF", quote: true, body: "$a\n", text: "$a\n", exp: "",
+		},
+		{
+			give:  "cat <<\"END\"\na\r\nEND\r\nx",
+			delim: "END", quote: true, body: "a\r\n", text: "a\r\n", exp: "x",
+		},
+		{
+			give:  "cat <<-EOF\n\t\ta\n\tb\n\tEOF\nx",
+			delim: "EOF", body: "\t\ta\n\tb\n", text: "a\nb\n", exp: "x",
+		},
+		{
+			give:  "x = <<~EOS\n    a\n      b\n\n    c\n  EOS\nx",
+			delim: "EOS", body: "    a\n      b\n\n    c\n", text: "a\n  b\n\nc\n", exp: "x",
+		},
+		{
+			give:  "$x = <<<'EOT'\n  a\n  EOT;\n",
+			delim: "EOT", quote: true, body: "  a\n", text: "a\n", exp: ";\n",
+		},
+	}
+	for _, tc := range tt {
+		var h Heredoc
+		s := Scanner(tc.give)
+		assertEqual(t, true, s.MatchUntil("<<"), tc.give)
+		assertEqual(t, true, s.UtilMatchHeredocStart(&h), tc.give)
+		assertEqual(t, tc.delim, h.Delim, tc.give)
+		assertEqual(t, tc.quote, h.Quoted, tc.give)
+		s.skipLine()
+		assertEqual(t, true, s.UtilMatchHeredocBody(&h), tc.give)
+		assertEqual(t, tc.body, h.Body, tc.give)
+		assertEqual(t, tc.text, h.Text(), tc.give)
+		assertEqual(t, tc.exp, s.String(), tc.give)
+	}
+}
+
+func TestScannerUtilMatchHeredocFail(t *testing.T) {
+	var h Heredoc
+	s := Scanner(`<<`)
+	assertEqual(t, false, s.UtilMatchHeredocStart(&h))
+	s = Scanner(`<<'EOF`)
+	assertEqual(t, false, s.UtilMatchHeredocStart(&h))
+	s = Scanner("<<EOF\na\nEOFX\n")
+	assertEqual(t, true, s.UtilMatchHeredocStart(&h))
+	s.skipLine()
+	assertEqual(t, false, s.UtilMatchHeredocBody(&h))
+	assertEqual(t, "a\nEOFX\n", s.String())
+}
+
+func BenchmarkScannerUtilMatchHeredoc(b *testing.B) {
+	x := Scanner("<<EOF\na\nb\nEOF\n")
+	for i := 0; i < b.N; i++ {
+		var h Heredoc
+		s := x
+		s.UtilMatchHeredocStart(&h)
+		s.skipLine()
+		s.UtilMatchHeredocBody(&h)
+	}
+}
+
+// #endregion RawString
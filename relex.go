@@ -0,0 +1,95 @@
+package scanner
+
+// #region Relex
+
+// Edit is a text edit.
+type Edit struct {
+	Off int    // Offset of the edit in the old text.
+	Del int    // Length of the deleted text.
+	Ins string // Inserted text.
+}
+
+// Apply returns the text after the edit.
+func (e Edit) Apply(old string) string {
+	return old[:e.Off] + e.Ins + old[e.Off+e.Del:]
+}
+
+// Relexer lexes a text and re-lexes it after edits,
+// scanning only the tokens around the edit.
+type Relexer struct {
+	// State is the start state of the lexer.
+	State StateFn
+	// Safe tells if the lexer is at State at the
+	// start of a token, so lexing can restart there.
+	// Eg: tokens inside a template string are not safe.
+	// Nil means every token is safe.
+	Safe func(Token) bool
+}
+
+// Lex returns the tokens of src, EOF excluded.
+func (r *Relexer) Lex(src string) []Token {
+	return r.lex(nil, src, 0, nil, Edit{})
+}
+
+// Relex returns the tokens of src, the text after an edit,
+// given the old tokens. It restarts at the last safe token
+// before the edit and stops when a new token matches an old
+// one past the edit, reusing the rest with shifted offsets.
+func (r *Relexer) Relex(old []Token, e Edit, src string) []Token {
+	// Restart point. Tokens touching the edit are
+	// lexed again, since they may merge with it. An
+	// edit in the whitespace before the token k is
+	// lexed from the edit, or it would be skipped.
+	k := 0
+	for k < len(old) && old[k].Span.End < e.Off {
+		k++
+	}
+	for k > 0 && (k == len(old) || !r.safe(old[k])) {
+		k--
+	}
+	off := 0
+	if k > 0 {
+		off = old[k].Span.Start
+		if e.Off < off {
+			off = e.Off
+		}
+	}
+	toks := make([]Token, k, len(old)+4)
+	copy(toks, old[:k])
+	return r.lex(toks, src, off, old[k:], e)
+}
+
+// lex lexes src from off, appending to toks. Once past the
+// edit, it resyncs with the old tokens in rest, if any.
+func (r *Relexer) lex(toks []Token, src string, off int, rest []Token, e Edit) []Token {
+	shift := len(e.Ins) - e.Del
+	l := NewLexer(src, r.State)
+	l.Scanner = l.Scanner[off:]
+	l.start = l.Scanner
+	j := 0
+	for t := l.NextToken(); t.Kind != KindEOF; t = l.NextToken() {
+		if rest != nil && t.Span.Start >= e.Off+len(e.Ins) {
+			for j < len(rest) && rest[j].Span.Start+shift < t.Span.Start {
+				j++
+			}
+			if j < len(rest) && rest[j].Span.Start >= e.Off+e.Del && rest[j].Kind == t.Kind &&
+				rest[j].Span.Start+shift == t.Span.Start && rest[j].Span.Len() == t.Span.Len() &&
+				len(l.buf) == 0 && r.safe(t) {
+				for _, o := range rest[j:] {
+					o.Span.Start += shift
+					o.Span.End += shift
+					toks = append(toks, o)
+				}
+				return toks
+			}
+		}
+		toks = append(toks, t)
+	}
+	return toks
+}
+
+func (r *Relexer) safe(t Token) bool {
+	return r.Safe == nil || r.Safe(t)
+}
+
+// #endregion Relex
@@ -0,0 +1,146 @@
+package scanner
+
+import (
+	"strings"
+	"testing"
+)
+
+// #region Relex
+
+func TestRelexerRelex(t *testing.T) {
+	src := "a = 1; bb = 22; ccc = 333; d = 4;"
+	tt := []struct {
+		give Edit
+		lexd int // Tokens lexed again.
+	}{
+		{give: Edit{Off: 7, Del: 2, Ins: "xy"}, lexd: 2},
+		{give: Edit{Off: 7, Del: 0, Ins: "x"}, lexd: 2},
+		{give: Edit{Off: 8, Del: 0, Ins: " "}, lexd: 3},
+		{give: Edit{Off: 6, Del: 1, Ins: ""}, lexd: 2},
+		{give: Edit{Off: 0, Del: 0, Ins: "z = 0; "}, lexd: 5},
+		{give: Edit{Off: len(src), Del: 0, Ins: " e = 5;"}, lexd: 5},
+		{give: Edit{Off: 12, Del: 5, Ins: "9"}, lexd: 3},
+		{give: Edit{Off: 0, Del: len(src), Ins: "x"}, lexd: 1},
+		{give: Edit{Off: 14, Del: 0, Ins: "; y"}, lexd: 4},
+		{give: Edit{Off: 21, Del: 0, Ins: "x "}, lexd: 3},
+		{give: Edit{Off: 16, Del: 0, Ins: "y"}, lexd: 2},
+		{give: Edit{Off: 15, Del: 1, Ins: ""}, lexd: 2},
+		{give: Edit{Off: 20, Del: 2, Ins: ""}, lexd: 1},
+	}
+	for _, tc := range tt {
+		// Counts the tokens lexed. The last run
+		// of the state, at the end, emits none.
+		lexed := 0
+		var count StateFn
+		count = func(l *Lexer) StateFn {
+			if lexAny(l) == nil {
+				return nil
+			}
+			lexed++
+			return count
+		}
+		r := Relexer{State: count}
+		old := r.Lex(src)
+		lexed = 0
+		got := r.Relex(old, tc.give, tc.give.Apply(src))
+		assertEqual(t, tc.lexd, lexed, tc.give)
+		assertEqual(t, r.Lex(tc.give.Apply(src)), got, tc.give)
+	}
+}
+
+func TestRelexerWhitespace(t *testing.T) {
+	r := Relexer{State: lexAny}
+	src := "a   b"
+	tt := []struct {
+		give Edit
+		exp  []Token
+	}{
+		{
+			give: Edit{Off: 2, Del: 0, Ins: "x"},
+			exp:  []Token{{kindIdent, "a", Span{0, 1}}, {kindIdent, "x", Span{2, 3}}, {kindIdent, "b", Span{5, 6}}},
+		},
+		{
+			give: Edit{Off: 3, Del: 0, Ins: "x"},
+			exp:  []Token{{kindIdent, "a", Span{0, 1}}, {kindIdent, "x", Span{3, 4}}, {kindIdent, "b", Span{5, 6}}},
+		},
+		{
+			give: Edit{Off: 1, Del: 2, Ins: ""},
+			exp:  []Token{{kindIdent, "a", Span{0, 1}}, {kindIdent, "b", Span{2, 3}}},
+		},
+		{
+			give: Edit{Off: 2, Del: 2, Ins: ""},
+			exp:  []Token{{kindIdent, "a", Span{0, 1}}, {kindIdent, "b", Span{2, 3}}},
+		},
+		{
+			give: Edit{Off: 1, Del: 3, Ins: ""},
+			exp:  []Token{{kindIdent, "ab", Span{0, 2}}},
+		},
+	}
+	for _, tc := range tt {
+		got := r.Relex(r.Lex(src), tc.give, tc.give.Apply(src))
+		assertEqual(t, tc.exp, got, tc.give)
+	}
+}
+
+func TestRelexerSafe(t *testing.T) {
+	// Words inside quotes are tokens too,
+	// so a quote edit changes everything after it.
+	kindQuote, kindWord, kindName := NewKind("Quote"), NewKind("Word"), NewKind("Name")
+	var quoted bool
+	var lex StateFn
+	lex = func(l *Lexer) StateFn {
+		l.WS()
+		l.Ignore()
+		switch {
+		case !l.More():
+			return nil
+		case l.MatchByte('"'):
+			quoted = !quoted
+			l.Emit(kindQuote)
+		default:
+			l.MatchWhileByteBy(func(c byte) bool { return c > ' ' && c != '"' })
+			if quoted {
+				l.Emit(kindWord)
+			} else {
+				l.Emit(kindName)
+			}
+		}
+		return lex
+	}
+	r := Relexer{
+		State: func(l *Lexer) StateFn {
+			quoted = false
+			return lex(l)
+		},
+		Safe: func(t Token) bool { return t.Kind == kindName },
+	}
+	src := `a "b c" d e f`
+	e := Edit{Off: 2, Del: 1, Ins: ""}
+	old := r.Lex(src)
+	got := r.Relex(old, e, e.Apply(src))
+	exp := r.Lex(e.Apply(src))
+	assertEqual(t, exp, got)
+	assertEqual(t, `a b c " d e f`, tokensText(got))
+}
+
+func tokensText(toks []Token) string {
+	var b []string
+	for _, t := range toks {
+		b = append(b, t.Text)
+	}
+	return strings.Join(b, " ")
+}
+
+func BenchmarkRelexerRelex(b *testing.B) {
+	src := strings.Repeat("a = 1; ", 10000)
+	r := Relexer{State: lexAny}
+	old := r.Lex(src)
+	e := Edit{Off: len(src) / 2, Del: 0, Ins: "x"}
+	src = e.Apply(src)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Relex(old, e, src)
+	}
+}
+
+// #endregion Relex
@@ -1,6 +1,7 @@
 package scanner
 
 import (
+	"unicode"
 	"unicode/utf8"
 )
 
@@ -48,6 +49,12 @@ func (s Scanner) EqualByteRange(a, b byte) bool {
 	return c >= a && c <= b
 }
 
+// EqualFold tests the current token given a string
+// under simple Unicode case folding.
+func (s Scanner) EqualFold(v string) bool {
+	return foldPrefix(s, v) >= 0
+}
+
 // #endregion Equal
 
 // #region Match
@@ -97,6 +104,16 @@ func (s *Scanner) MatchRuneBy(f func(rune) bool) bool {
 	return false
 }
 
+// MatchFold matches a token given a string
+// under simple Unicode case folding.
+func (s *Scanner) MatchFold(v string) bool {
+	if n := foldPrefix(*s, v); n >= 0 {
+		*s = (*s)[n:]
+		return true
+	}
+	return false
+}
+
 // #endregion Match
 
 // #region Until
@@ -113,11 +130,26 @@ func (s *Scanner) MatchUntil(v string) bool {
 			}
 			continue
 		}
+		a -= b
 		b = 0
 	}
 	return false
 }
 
+// MatchUntilFold matches until v matches
+// under simple Unicode case folding.
+func (s *Scanner) MatchUntilFold(v string) bool {
+	ss := *s
+	for i := 0; i < len(ss); {
+		if foldPrefix(ss[i:], v) >= 0 {
+			*s = ss[i:]
+			return true
+		}
+		i += runeLen(ss[i:])
+	}
+	return false
+}
+
 // MatchUntilByte matches until v matches.
 func (s *Scanner) MatchUntilByte(v byte) bool {
 	ss := *s
@@ -195,6 +227,20 @@ func (s *Scanner) MatchUntilAny(a, b string) bool {
 	return false
 }
 
+// MatchUntilAnyFold matches until either a or b
+// matches under simple Unicode case folding.
+func (s *Scanner) MatchUntilAnyFold(a, b string) bool {
+	ss := *s
+	for i := 0; i < len(ss); {
+		if foldPrefix(ss[i:], a) >= 0 || foldPrefix(ss[i:], b) >= 0 {
+			*s = ss[i:]
+			return true
+		}
+		i += runeLen(ss[i:])
+	}
+	return false
+}
+
 // MatchUntilAnyByte matches until either a or b matches.
 func (s *Scanner) MatchUntilAnyByte(a, b byte) bool {
 	ss := *s
@@ -541,6 +587,71 @@ func (s Scanner) Bytes() []byte {
 	// return (*[1 << 30]byte)(unsafe.Pointer((*reflect.StringHeader)(unsafe.Pointer(&s)).Data))[:len(s):len(s)]
 }
 
+// foldPrefix returns the length of the prefix of ss that
+// equals v under simple Unicode case folding, or -1.
+func foldPrefix(ss Scanner, v string) int {
+	i, j := 0, 0
+	for j < len(v) {
+		if i == len(ss) {
+			return -1
+		}
+		// ASCII fast path.
+		if a, b := ss[i], v[j]; a|b < utf8.RuneSelf {
+			if a != b && lower(a) != lower(b) {
+				return -1
+			}
+			i++
+			j++
+			continue
+		}
+		a, n := utf8.DecodeRuneInString(ss[i:].String())
+		b, m := utf8.DecodeRuneInString(v[j:])
+		// Invalid bytes all decode to RuneError,
+		// so they are compared as they are.
+		if a == utf8.RuneError && n == 1 || b == utf8.RuneError && m == 1 {
+			if n != m || ss[i] != v[j] {
+				return -1
+			}
+		} else if !equalFoldRune(a, b) {
+			return -1
+		}
+		i += n
+		j += m
+	}
+	return i
+}
+
+// equalFoldRune tells if a and b are equal
+// under simple Unicode case folding.
+func equalFoldRune(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	for r := unicode.SimpleFold(a); r != a; r = unicode.SimpleFold(r) {
+		if r == b {
+			return true
+		}
+	}
+	return false
+}
+
+// lower returns the ASCII lowercase of c.
+func lower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// runeLen returns the length of the current rune.
+func runeLen(ss Scanner) int {
+	if len(ss) > 0 && ss[0] < utf8.RuneSelf {
+		return 1
+	}
+	_, size := utf8.DecodeRuneInString(ss.String())
+	return size
+}
+
 // #endregion Miscellaneous
 
 // #region Util
@@ -168,6 +168,39 @@ func BenchmarkScannerEqualByteRange(b *testing.B) {
 	}
 }
 
+func TestScannerEqualFold(t *testing.T) {
+	tt := []struct {
+		give string
+		when string
+		then bool
+	}{
+		{give: `abc`, when: `abc`, then: true},
+		{give: `ABC`, when: `abc`, then: true},
+		{give: `aBcd`, when: `AbC`, then: true},
+		{give: `ÀB`, when: `àb`, then: true},
+		{give: "\u212A", when: `k`, then: true},
+		{give: `ς`, when: `Σ`, then: true},
+		{give: `ab`, when: `abc`, then: false},
+		{give: `abd`, when: `abc`, then: false},
+		{give: "\xff", when: "\xfe", then: false},
+		{give: "a\xffb", when: "A\xffB", then: true},
+		{give: "\xff", when: "\uFFFD", then: false},
+		{give: ``, when: ``, then: true},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		assertEqual(t, tc.then, s.EqualFold(tc.when), tc)
+	}
+}
+
+func BenchmarkScannerEqualFold(b *testing.B) {
+	x := Scanner(`Content-Type`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.EqualFold("content-type")
+	}
+}
+
 // #endregion Equal
 
 // #region Match
@@ -315,6 +348,33 @@ func BenchmarkScannerMatchRuneBy(b *testing.B) {
 	}
 }
 
+func TestScannerMatchFold(t *testing.T) {
+	tt := []struct {
+		give string
+		when string
+		then bool
+		exp  string
+	}{
+		{give: `SELECT *`, when: "select", then: true, exp: `SELECT`},
+		{give: "\u212Aa", when: "kA", then: true, exp: "\u212Aa"},
+		{give: `SELEC`, when: "select", then: false, exp: ``},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		m := s.Mark()
+		assertEqual(t, tc.then, s.MatchFold(tc.when), tc)
+		assertEqual(t, tc.exp, s.Token(m), tc)
+	}
+}
+
+func BenchmarkScannerMatchFold(b *testing.B) {
+	x := Scanner(`SELECT`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.MatchFold("select")
+	}
+}
+
 // #endregion Match
 
 // #region Until
@@ -329,6 +389,8 @@ func TestScannerMatchUntil(t *testing.T) {
 		{give: `abc.`, when: ".", then: true, exp: "abc"},
 		{give: `a.b..cd...`, when: "...", then: true, exp: "a.b..cd"},
 		{give: `abc?`, when: ".", then: false, exp: ""},
+		{give: `a **/`, when: "*/", then: true, exp: "a *"},
+		{give: `aab`, when: "ab", then: true, exp: "a"},
 	}
 	for _, tc := range tt {
 		s := Scanner(tc.give)
@@ -346,6 +408,34 @@ func BenchmarkScannerMatchUntil(b *testing.B) {
 	}
 }
 
+func TestScannerMatchUntilFold(t *testing.T) {
+	tt := []struct {
+		give string
+		when string
+		then bool
+		exp  string
+	}{
+		{give: `var x</SCRIPT>`, when: "</script>", then: true, exp: `var x`},
+		{give: `aé1ÉX`, when: "éx", then: true, exp: `aé1`},
+		{give: `abc`, when: "x", then: false, exp: ``},
+		{give: ``, when: "x", then: false, exp: ``},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		m := s.Mark()
+		assertEqual(t, tc.then, s.MatchUntilFold(tc.when), tc)
+		assertEqual(t, tc.exp, s.Token(m), tc)
+	}
+}
+
+func BenchmarkScannerMatchUntilFold(b *testing.B) {
+	x := Scanner(`var x = 1;</SCRIPT>`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.MatchUntilFold("</script>")
+	}
+}
+
 func TestScannerMatchUntilByte(t *testing.T) {
 	tt := []struct {
 		give string
@@ -492,6 +582,33 @@ func BenchmarkScannerMatchUntilAny(b *testing.B) {
 	}
 }
 
+func TestScannerMatchUntilAnyFold(t *testing.T) {
+	tt := []struct {
+		give string
+		when []string
+		then bool
+		exp  string
+	}{
+		{give: `a WHERE b`, when: []string{"where", "order"}, then: true, exp: `a `},
+		{give: `a Order b`, when: []string{"where", "order"}, then: true, exp: `a `},
+		{give: `a b`, when: []string{"where", "order"}, then: false, exp: ``},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		m := s.Mark()
+		assertEqual(t, tc.then, s.MatchUntilAnyFold(tc.when[0], tc.when[1]), tc)
+		assertEqual(t, tc.exp, s.Token(m), tc)
+	}
+}
+
+func BenchmarkScannerMatchUntilAnyFold(b *testing.B) {
+	x := Scanner(`a b c ORDER`)
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.MatchUntilAnyFold("where", "order")
+	}
+}
+
 func TestScannerMatchUntilAnyByte(t *testing.T) {
 	tt := []struct {
 		give string
@@ -0,0 +1,122 @@
+package scanner
+
+import (
+	"strconv"
+	"sync"
+)
+
+// #region Token
+
+// Kind is a token kind, created with NewKind.
+// Negative kinds are reserved.
+type Kind int
+
+const (
+	KindEOF   Kind = -1 // The input ended.
+	KindError Kind = -2 // An error. The text is the message.
+)
+
+var kinds struct {
+	sync.RWMutex
+	names []string
+}
+
+// NewKind registers a new token kind with a
+// name for debug output. Eg:
+//
+//	var Ident = scanner.NewKind("Ident")
+func NewKind(name string) Kind {
+	kinds.Lock()
+	defer kinds.Unlock()
+	kinds.names = append(kinds.names, name)
+	return Kind(len(kinds.names) - 1)
+}
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case KindEOF:
+		return "EOF"
+	case KindError:
+		return "Error"
+	}
+	kinds.RLock()
+	defer kinds.RUnlock()
+	if k >= 0 && int(k) < len(kinds.names) {
+		return kinds.names[k]
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Token is a scanned token.
+type Token struct {
+	Kind Kind
+	Text string
+	Span Span
+}
+
+// String returns the token for debug output,
+// like Ident "abc" 0:3.
+func (t Token) String() string {
+	return t.Kind.String() + " " + strconv.Quote(t.Text) + " " + t.Span.String()
+}
+
+// Span is a range of byte offsets
+// from the start of the input.
+type Span struct {
+	Start, End int
+}
+
+// Span returns the span between two marks of src.
+// The receiver is the start of the input.
+func (src Scanner) Span(ini, end Scanner) Span {
+	return Span{Start: len(src) - len(ini), End: len(src) - len(end)}
+}
+
+// Len returns the length of the span.
+func (s Span) Len() int {
+	return s.End - s.Start
+}
+
+// Merge returns the span that covers s and o.
+func (s Span) Merge(o Span) Span {
+	if o.Start < s.Start {
+		s.Start = o.Start
+	}
+	if o.End > s.End {
+		s.End = o.End
+	}
+	return s
+}
+
+// Contains tells if off is in the span.
+func (s Span) Contains(off int) bool {
+	return off >= s.Start && off < s.End
+}
+
+// Text returns the text of the span in src.
+func (s Span) Text(src string) string {
+	return src[s.Start:s.End]
+}
+
+func (s Span) String() string {
+	return strconv.Itoa(s.Start) + ":" + strconv.Itoa(s.End)
+}
+
+// LineCol returns the 1-based line and column of an
+// offset. The receiver is the start of the input.
+// Columns count bytes.
+func (src Scanner) LineCol(off int) (line, col int) {
+	line, col = 1, 1
+	for i := 0; i < off && i < len(src); i++ {
+		if src[i] == '\n' {
+			line++
+			col = 1
+			continue
+		}
+		col++
+	}
+	return line, col
+}
+
+// #endregion Token
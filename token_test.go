@@ -0,0 +1,80 @@
+package scanner
+
+import "testing"
+
+// #region Token
+
+func TestKindString(t *testing.T) {
+	k := NewKind("Test")
+	assertEqual(t, "Test", k.String())
+	assertEqual(t, "EOF", KindEOF.String())
+	assertEqual(t, "Error", KindError.String())
+	assertEqual(t, "Kind(-9)", Kind(-9).String())
+	assertEqual(t, "Kind(99999)", Kind(99999).String())
+}
+
+func TestTokenString(t *testing.T) {
+	tok := Token{Kind: KindError, Text: "a\n", Span: Span{1, 3}}
+	assertEqual(t, `Error "a\n" 1:3`, tok.String())
+}
+
+func TestScannerSpan(t *testing.T) {
+	src := Scanner(`abc def`)
+	s := src
+	s.MatchUntilByte(' ')
+	s.Next()
+	m := s.Mark()
+	s.MatchUntilByte('f')
+	sp := src.Span(m, s)
+	assertEqual(t, Span{4, 6}, sp)
+	assertEqual(t, 2, sp.Len())
+	assertEqual(t, "de", sp.Text(src.String()))
+	assertEqual(t, "4:6", sp.String())
+}
+
+func TestSpanMerge(t *testing.T) {
+	tt := []struct {
+		give Span
+		when Span
+		then Span
+	}{
+		{give: Span{1, 3}, when: Span{5, 8}, then: Span{1, 8}},
+		{give: Span{5, 8}, when: Span{1, 3}, then: Span{1, 8}},
+		{give: Span{1, 8}, when: Span{3, 5}, then: Span{1, 8}},
+	}
+	for _, tc := range tt {
+		assertEqual(t, tc.then, tc.give.Merge(tc.when), tc)
+	}
+}
+
+func TestSpanContains(t *testing.T) {
+	sp := Span{2, 4}
+	assertEqual(t, false, sp.Contains(1))
+	assertEqual(t, true, sp.Contains(2))
+	assertEqual(t, true, sp.Contains(3))
+	assertEqual(t, false, sp.Contains(4))
+}
+
+func TestScannerLineCol(t *testing.T) {
+	src := Scanner("ab\ncd\n\ne")
+	tt := []struct {
+		give int
+		line int
+		col  int
+	}{
+		{give: 0, line: 1, col: 1},
+		{give: 1, line: 1, col: 2},
+		{give: 2, line: 1, col: 3},
+		{give: 3, line: 2, col: 1},
+		{give: 6, line: 3, col: 1},
+		{give: 7, line: 4, col: 1},
+		{give: 8, line: 4, col: 2},
+	}
+	for _, tc := range tt {
+		line, col := src.LineCol(tc.give)
+		assertEqual(t, tc.line, line, tc)
+		assertEqual(t, tc.col, col, tc)
+	}
+}
+
+// #endregion Token
@@ -0,0 +1,344 @@
+// Package toml decodes TOML 1.0 documents with the scanner.
+package toml
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/ofabricio/scanner"
+)
+
+// #region Errors
+
+var (
+	ErrKey       = errors.New("invalid key")
+	ErrEqual     = errors.New("expected '=' after key")
+	ErrValue     = errors.New("invalid value")
+	ErrString    = errors.New("unclosed string")
+	ErrEscape    = errors.New("invalid escape")
+	ErrNumber    = errors.New("invalid number")
+	ErrDate      = errors.New("invalid date-time")
+	ErrArray     = errors.New("expected ',' or ']' in array")
+	ErrTable     = errors.New("expected ',' or '}' in inline table")
+	ErrHeader    = errors.New("expected ']' after table name")
+	ErrNewline   = errors.New("expected a newline")
+	ErrDuplicate = errors.New("duplicate key")
+	ErrNotTable  = errors.New("key is not a table")
+)
+
+// ParseError is a positioned decoding error. Key is the
+// dotted key of ErrDuplicate and ErrNotTable errors.
+type ParseError struct {
+	scanner.ParseError
+	// PrevLine and PrevCol are the position of the
+	// first definition of a duplicate key.
+	PrevLine, PrevCol int
+}
+
+func (e *ParseError) Error() string {
+	msg := e.ParseError.Error()
+	if e.PrevLine > 0 {
+		msg += ", first defined at " + strconv.Itoa(e.PrevLine) + ":" + strconv.Itoa(e.PrevCol)
+	}
+	return msg
+}
+
+// #endregion Errors
+
+// #region Decode
+
+// Decode decodes a TOML document. Tables are map[string]any,
+// arrays are []any and arrays of tables are []map[string]any.
+// Strings are string, integers int64, floats float64 and
+// booleans bool. Offset date-times are time.Time and local
+// ones are LocalDateTime, LocalDate and LocalTime.
+func Decode(src string) (map[string]any, error) {
+	s := scanner.Scanner(src)
+	p := parser{src: s, s: s, root: newTable()}
+	cur := p.root
+	for {
+		p.wsnl()
+		if !p.s.More() {
+			break
+		}
+		var err error
+		switch {
+		case p.s.Equal("[["):
+			cur, err = p.header(true)
+		case p.s.EqualByte('['):
+			cur, err = p.header(false)
+		default:
+			err = p.keyval(cur)
+		}
+		if err == nil {
+			err = p.eol()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return p.root.export(), nil
+}
+
+// table is a table being decoded. Values are
+// any of the decoded types, *table and *tables.
+type table struct {
+	m   map[string]any
+	pos map[string]int // Offset of the keys.
+	// How the table was defined.
+	header bool // By a [header].
+	dotted bool // By dotted keys.
+	inline bool // As an inline table. It is closed.
+}
+
+// tables is an array of tables.
+type tables struct {
+	list []*table
+}
+
+func newTable() *table {
+	return &table{m: map[string]any{}, pos: map[string]int{}}
+}
+
+type parser struct {
+	src, s scanner.Scanner
+	root   *table
+}
+
+// header decodes a [table] or [[array]] header
+// and returns the table of the following keys.
+func (p *parser) header(array bool) (*table, error) {
+	at := p.s
+	p.s.Next()
+	if array {
+		p.s.Next()
+	}
+	p.ws()
+	keys, err := p.key()
+	if err != nil {
+		return nil, err
+	}
+	p.ws()
+	if !p.s.MatchByte(']') || array && !p.s.MatchByte(']') {
+		return nil, p.errorf(p.s, ErrHeader)
+	}
+	t := p.root
+	for i := range keys[:len(keys)-1] {
+		if t, err = p.sub(t, keys[:i+1], at, false); err != nil {
+			return nil, err
+		}
+	}
+	k := keys[len(keys)-1]
+	v, ok := t.m[k]
+	if !ok {
+		n := newTable()
+		n.header = true
+		if array {
+			t.m[k] = &tables{list: []*table{n}}
+		} else {
+			t.m[k] = n
+		}
+		t.pos[k] = p.off(at)
+		return n, nil
+	}
+	switch v := v.(type) {
+	case *tables:
+		if array {
+			n := newTable()
+			n.header = true
+			v.list = append(v.list, n)
+			return n, nil
+		}
+	case *table:
+		if !array && !v.header && !v.dotted && !v.inline {
+			v.header = true
+			return v, nil
+		}
+	}
+	return nil, p.duplicate(t, keys, at)
+}
+
+// keyval decodes a key/value pair into t.
+func (p *parser) keyval(t *table) error {
+	at := p.s
+	keys, err := p.key()
+	if err != nil {
+		return err
+	}
+	p.ws()
+	if !p.s.MatchByte('=') {
+		return p.errorf(p.s, ErrEqual)
+	}
+	p.ws()
+	v, err := p.value()
+	if err != nil {
+		return err
+	}
+	for i := range keys[:len(keys)-1] {
+		if t, err = p.sub(t, keys[:i+1], at, true); err != nil {
+			return err
+		}
+	}
+	k := keys[len(keys)-1]
+	if _, ok := t.m[k]; ok {
+		return p.duplicate(t, keys, at)
+	}
+	t.m[k], t.pos[k] = v, p.off(at)
+	return nil
+}
+
+// sub returns the sub-table of t at the last key of path,
+// creating it if missing. Dotted keys only go in tables
+// they defined. Headers go in the last table of an array.
+func (p *parser) sub(t *table, path []string, at scanner.Scanner, dotted bool) (*table, error) {
+	k := path[len(path)-1]
+	v, ok := t.m[k]
+	if !ok {
+		n := newTable()
+		n.dotted = dotted
+		t.m[k], t.pos[k] = n, p.off(at)
+		return n, nil
+	}
+	switch v := v.(type) {
+	case *table:
+		if v.inline || dotted && !v.dotted {
+			return nil, p.duplicate(t, path, at)
+		}
+		return v, nil
+	case *tables:
+		if !dotted {
+			return v.list[len(v.list)-1], nil
+		}
+	}
+	line, col := p.src.LineCol(p.off(at))
+	return nil, &ParseError{ParseError: scanner.ParseError{Line: line, Col: col, Err: ErrNotTable, Pkg: "toml", Key: strings.Join(path, ".")}}
+}
+
+// duplicate returns a duplicate key error for the
+// last key of path, which is already in t.
+func (p *parser) duplicate(t *table, path []string, at scanner.Scanner) error {
+	line, col := p.src.LineCol(p.off(at))
+	pl, pc := p.src.LineCol(t.pos[path[len(path)-1]])
+	return &ParseError{
+		ParseError: scanner.ParseError{Line: line, Col: col, Err: ErrDuplicate, Pkg: "toml", Key: strings.Join(path, ".")},
+		PrevLine:   pl,
+		PrevCol:    pc,
+	}
+}
+
+// key decodes a dotted key.
+func (p *parser) key() ([]string, error) {
+	var keys []string
+	for {
+		p.ws()
+		var k string
+		var err error
+		switch c := p.s.Curr(); {
+		case !p.s.More():
+			return nil, p.errorf(p.s, ErrKey)
+		case c == '"' && !p.s.Equal(`"""`):
+			k, err = p.basic()
+		case c == '\'' && !p.s.Equal(`'''`):
+			k, err = p.literal()
+		default:
+			if k = p.s.TokenByteBy(isBare); k == "" {
+				return nil, p.errorf(p.s, ErrKey)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+		p.ws()
+		if !p.s.MatchByte('.') {
+			return keys, nil
+		}
+	}
+}
+
+// ws skips spaces and tabs.
+func (p *parser) ws() {
+	p.s.MatchWhileByteBy(isSpace)
+}
+
+// wsnl skips whitespaces, comments and line breaks.
+func (p *parser) wsnl() {
+	for {
+		p.ws()
+		switch {
+		case p.s.EqualByte('#'):
+			p.comment()
+		case p.s.MatchByte('\n'), p.s.Match("\r\n"):
+		default:
+			return
+		}
+	}
+}
+
+// comment skips a comment up to the line break.
+func (p *parser) comment() {
+	if !p.s.MatchUntilByte('\n') {
+		p.s.Advance(len(p.s))
+	}
+}
+
+// eol matches the end of a line, after an
+// optional comment, or the end of the input.
+func (p *parser) eol() error {
+	p.ws()
+	if p.s.EqualByte('#') {
+		p.comment()
+	}
+	if p.s.MatchByte('\n') || p.s.Match("\r\n") || !p.s.More() {
+		return nil
+	}
+	return p.errorf(p.s, ErrNewline)
+}
+
+func (p *parser) off(at scanner.Scanner) int {
+	return len(p.src) - len(at)
+}
+
+func (p *parser) errorf(at scanner.Scanner, err error) error {
+	line, col := p.src.LineCol(p.off(at))
+	return &ParseError{ParseError: scanner.ParseError{Line: line, Col: col, Err: err, Pkg: "toml"}}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+func isBare(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// export returns t as a map.
+func (t *table) export() map[string]any {
+	m := make(map[string]any, len(t.m))
+	for k, v := range t.m {
+		m[k] = exportValue(v)
+	}
+	return m
+}
+
+func exportValue(v any) any {
+	switch v := v.(type) {
+	case *table:
+		return v.export()
+	case *tables:
+		list := make([]map[string]any, len(v.list))
+		for i, t := range v.list {
+			list[i] = t.export()
+		}
+		return list
+	case []any:
+		for i := range v {
+			v[i] = exportValue(v[i])
+		}
+		return v
+	}
+	return v
+}
+
+// #endregion Decode
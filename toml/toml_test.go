@@ -0,0 +1,130 @@
+package toml
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// #region Decode
+
+func TestDecode(t *testing.T) {
+	src := `# comment
+title = "TOML"
+"quoted key" = 1
+site."google.com" = true
+
+[owner]
+name = 'Tom'
+a.b.c = 1
+
+[servers.alpha]
+ip = "10.0.0.1"
+ports = [ 8000, 8001,
+  8002, # trailing comma
+]
+[servers.beta]
+point = { x = 1, y.z = 2 }
+
+[[products]]
+name = "Hammer"
+[products.size]
+w = 1
+[[products]]
+name = "Nail"
+`
+	got, err := Decode(src)
+	assertEqual(t, nil, err)
+	assertEqual(t, map[string]any{
+		"title":      "TOML",
+		"quoted key": int64(1),
+		"site":       map[string]any{"google.com": true},
+		"owner": map[string]any{
+			"name": "Tom",
+			"a":    map[string]any{"b": map[string]any{"c": int64(1)}},
+		},
+		"servers": map[string]any{
+			"alpha": map[string]any{"ip": "10.0.0.1", "ports": []any{int64(8000), int64(8001), int64(8002)}},
+			"beta":  map[string]any{"point": map[string]any{"x": int64(1), "y": map[string]any{"z": int64(2)}}},
+		},
+		"products": []map[string]any{
+			{"name": "Hammer", "size": map[string]any{"w": int64(1)}},
+			{"name": "Nail"},
+		},
+	}, got)
+}
+
+func TestDecodeTables(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  map[string]any
+	}{
+		{give: "[a.b]\n[a]\nx=1", exp: map[string]any{"a": map[string]any{"x": int64(1), "b": map[string]any{}}}},
+		{give: "[a]\nb.c=1\nb.d=2", exp: map[string]any{"a": map[string]any{"b": map[string]any{"c": int64(1), "d": int64(2)}}}},
+		{give: "[[a]]\n[[a.b]]\nx=1\n[[a.b]]\n[[a]]", exp: map[string]any{"a": []map[string]any{{"b": []map[string]any{{"x": int64(1)}, {}}}, {}}}},
+		{give: "a = [{x=1}, []]", exp: map[string]any{"a": []any{map[string]any{"x": int64(1)}, []any{}}}},
+		{give: "[ a . 'b c' ]\r\nx=1\r\n", exp: map[string]any{"a": map[string]any{"b c": map[string]any{"x": int64(1)}}}},
+		{give: "a = {}", exp: map[string]any{"a": map[string]any{}}},
+	}
+	for _, tc := range tt {
+		got, err := Decode(tc.give)
+		assertEqual(t, nil, err, tc.give)
+		assertEqual(t, tc.exp, got, tc.give)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+		err  error
+	}{
+		{give: "a = 1\na = 2", exp: `toml: duplicate key "a" at 2:1, first defined at 1:1`, err: ErrDuplicate},
+		{give: "[a]\nx=1\n\n[a]", exp: `toml: duplicate key "a" at 4:1, first defined at 1:1`, err: ErrDuplicate},
+		{give: "a.b = 1\n[a]", exp: `toml: duplicate key "a" at 2:1, first defined at 1:1`, err: ErrDuplicate},
+		{give: "[a.b]\n[a]\nb.c = 1", exp: `toml: duplicate key "b" at 3:1, first defined at 1:1`, err: ErrDuplicate},
+		{give: "a = {x=1}\n[a.b]", exp: `toml: duplicate key "a" at 2:1, first defined at 1:1`, err: ErrDuplicate},
+		{give: "a = {x={}}\na.x.y = 1", exp: `toml: duplicate key "a" at 2:1, first defined at 1:1`, err: ErrDuplicate},
+		{give: "[[a]]\n[a]", exp: `toml: duplicate key "a" at 2:1, first defined at 1:1`, err: ErrDuplicate},
+		{give: "a = 1\na.b = 2", exp: `toml: key is not a table "a" at 2:1`, err: ErrNotTable},
+		{give: "a = 1 b = 2", exp: "toml: expected a newline at 1:7", err: ErrNewline},
+		{give: "= 1", exp: "toml: invalid key at 1:1", err: ErrKey},
+		{give: "a 1", exp: "toml: expected '=' after key at 1:3", err: ErrEqual},
+		{give: "a =", exp: "toml: invalid value at 1:4", err: ErrValue},
+		{give: "[a\nx=1", exp: "toml: expected ']' after table name at 1:3", err: ErrHeader},
+		{give: "a = [1 2]", exp: "toml: expected ',' or ']' in array at 1:8", err: ErrArray},
+		{give: "a = {x=1,}", exp: "toml: invalid key at 1:10", err: ErrKey},
+		{give: "a = {x=1\n}", exp: "toml: expected ',' or '}' in inline table at 1:9", err: ErrTable},
+	}
+	for _, tc := range tt {
+		_, err := Decode(tc.give)
+		if assertEqual(t, true, err != nil, tc.give) {
+			assertEqual(t, tc.exp, err.Error(), tc.give)
+			assertEqual(t, true, errors.Is(err, tc.err), tc.give)
+		}
+	}
+	_, err := Decode("a = 1\n[b]\nc = 2\nc = 3")
+	var pe *ParseError
+	assertEqual(t, true, errors.As(err, &pe))
+	assertEqual(t, "c", pe.Key)
+	assertEqual(t, [4]int{4, 1, 3, 1}, [4]int{pe.Line, pe.Col, pe.PrevLine, pe.PrevCol})
+}
+
+func BenchmarkDecode(b *testing.B) {
+	src := "title = \"x\"\n[server]\nhost = \"localhost\"\nports = [80, 443]\n[[user]]\nname = 'a'\nage = 30\n"
+	for i := 0; i < b.N; i++ {
+		Decode(src)
+	}
+}
+
+// #endregion Decode
+
+func assertEqual(t *testing.T, exp, got any, msgs ...any) bool {
+	t.Helper()
+	if !reflect.DeepEqual(exp, got) {
+		t.Errorf("\nExp:\n%v\nGot:\n%v\nMsg: %v", exp, got, fmt.Sprint(msgs...))
+		return false
+	}
+	return true
+}
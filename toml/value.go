@@ -0,0 +1,460 @@
+package toml
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ofabricio/scanner"
+)
+
+// #region Value
+
+// value decodes a value.
+func (p *parser) value() (any, error) {
+	m := p.s
+	switch c := p.s.Curr(); {
+	case !p.s.More():
+		return nil, p.errorf(p.s, ErrValue)
+	case p.s.Equal(`"""`):
+		return p.multiline('"')
+	case p.s.Equal(`'''`):
+		return p.multiline('\'')
+	case c == '"':
+		return p.basic()
+	case c == '\'':
+		return p.literal()
+	case c == '[':
+		return p.array()
+	case c == '{':
+		return p.inline()
+	case p.s.Match("true"):
+		return true, p.end(m)
+	case p.s.Match("false"):
+		return false, p.end(m)
+	case isDateTime(p.s):
+		return p.datetime()
+	}
+	return p.number()
+}
+
+// end checks that a value is not followed by bare key characters.
+func (p *parser) end(at scanner.Scanner) error {
+	if p.s.More() && (isBare(p.s.Curr()) || p.s.EqualByte('.') || p.s.EqualByte(':') || p.s.EqualByte('+')) {
+		return p.errorf(at, ErrValue)
+	}
+	return nil
+}
+
+// array decodes an array.
+func (p *parser) array() ([]any, error) {
+	p.s.Next()
+	list := []any{}
+	for {
+		p.wsnl()
+		if p.s.MatchByte(']') {
+			return list, nil
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+		p.wsnl()
+		if p.s.MatchByte(']') {
+			return list, nil
+		}
+		if !p.s.MatchByte(',') {
+			return nil, p.errorf(p.s, ErrArray)
+		}
+	}
+}
+
+// inline decodes an inline table. They take
+// no line breaks and no trailing comma.
+func (p *parser) inline() (*table, error) {
+	p.s.Next()
+	t := newTable()
+	p.ws()
+	if !p.s.MatchByte('}') {
+		for {
+			if err := p.keyval(t); err != nil {
+				return nil, err
+			}
+			p.ws()
+			if p.s.MatchByte('}') {
+				break
+			}
+			if !p.s.MatchByte(',') {
+				return nil, p.errorf(p.s, ErrTable)
+			}
+		}
+	}
+	t.close()
+	return t, nil
+}
+
+// close closes an inline table and its dotted sub-tables.
+func (t *table) close() {
+	t.inline = true
+	for _, v := range t.m {
+		if v, ok := v.(*table); ok {
+			v.close()
+		}
+	}
+}
+
+// #endregion Value
+
+// #region String
+
+// basic decodes a basic string.
+func (p *parser) basic() (string, error) {
+	ini := p.s
+	p.s.Next()
+	var b strings.Builder
+	for {
+		c := p.s.Curr()
+		switch {
+		case !p.s.More() || c == '\n' || c == '\r':
+			return "", p.errorf(ini, ErrString)
+		case c == '"':
+			p.s.Next()
+			return b.String(), nil
+		case c == '\\':
+			if err := p.escape(&b); err != nil {
+				return "", err
+			}
+		default:
+			m := p.s
+			p.s.MatchUntilAnyByte4('"', '\\', '\n', '\r')
+			if len(m) == len(p.s) {
+				p.s.Advance(len(p.s))
+			}
+			b.WriteString(p.s.Token(m))
+		}
+	}
+}
+
+// literal decodes a literal string.
+func (p *parser) literal() (string, error) {
+	ini := p.s
+	p.s.Next()
+	m := p.s
+	if !p.s.MatchUntilAnyByte3('\'', '\n', '\r') || !p.s.EqualByte('\'') {
+		return "", p.errorf(ini, ErrString)
+	}
+	v := p.s.Token(m)
+	p.s.Next()
+	return v, nil
+}
+
+// multiline decodes a multiline basic or literal
+// string. A line break right after the opening
+// quotes is trimmed. Up to two quotes can be
+// next to the closing ones.
+func (p *parser) multiline(q byte) (string, error) {
+	ini := p.s
+	p.s.Advance(3)
+	if !p.s.MatchByte('\n') {
+		p.s.Match("\r\n")
+	}
+	var b strings.Builder
+	for {
+		c := p.s.Curr()
+		switch {
+		case !p.s.More():
+			return "", p.errorf(ini, ErrString)
+		case c == q:
+			m := p.s
+			p.s.MatchWhileByteBy(func(c byte) bool { return c == q })
+			n := len(m) - len(p.s)
+			if n < 3 {
+				b.WriteString(p.s.Token(m))
+				continue
+			}
+			if n > 5 {
+				return "", p.errorf(m, ErrString)
+			}
+			b.WriteString(m[:n-3].String())
+			return b.String(), nil
+		case c == '\\' && q == '"':
+			// A line ending backslash trims the
+			// whitespaces up to the next text.
+			m := p.s
+			p.s.Next()
+			p.ws()
+			if p.s.MatchByte('\n') || p.s.Match("\r\n") {
+				p.s.MatchWhileByteBy(func(c byte) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' })
+				continue
+			}
+			p.s = m
+			if err := p.escape(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(c)
+			p.s.Next()
+		}
+	}
+}
+
+// escape decodes an escape sequence.
+func (p *parser) escape(b *strings.Builder) error {
+	ini := p.s
+	p.s.Next()
+	c := p.s.Curr()
+	p.s.Next()
+	switch c {
+	case 'b':
+		b.WriteByte('\b')
+	case 't':
+		b.WriteByte('\t')
+	case 'n':
+		b.WriteByte('\n')
+	case 'f':
+		b.WriteByte('\f')
+	case 'r':
+		b.WriteByte('\r')
+	case '"':
+		b.WriteByte('"')
+	case '\\':
+		b.WriteByte('\\')
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		r, ok := p.s.UtilParseHexRune(n)
+		if !ok {
+			return p.errorf(ini, ErrEscape)
+		}
+		b.WriteRune(r)
+	default:
+		return p.errorf(ini, ErrEscape)
+	}
+	return nil
+}
+
+// #endregion String
+
+// #region Number
+
+// number decodes an integer or a float.
+func (p *parser) number() (any, error) {
+	m := p.s
+	if !p.s.UtilMatchNumberFormat(scanner.NumberTOML) {
+		return nil, p.errorf(m, ErrValue)
+	}
+	if err := p.end(m); err != nil {
+		return nil, p.errorf(m, ErrNumber)
+	}
+	v := strings.ReplaceAll(p.s.Token(m), "_", "")
+	u := strings.TrimLeft(v, "+-")
+	switch {
+	case u == "inf":
+		if v[0] == '-' {
+			return math.Inf(-1), nil
+		}
+		return math.Inf(1), nil
+	case u == "nan":
+		return math.NaN(), nil
+	case len(u) > 1 && u[0] == '0' && (u[1] == 'x' || u[1] == 'o' || u[1] == 'b'):
+		base := map[byte]int{'x': 16, 'o': 8, 'b': 2}[u[1]]
+		n, err := strconv.ParseInt(u[2:], base, 64)
+		if err != nil {
+			return nil, p.errorf(m, ErrNumber)
+		}
+		return n, nil
+	case strings.ContainsAny(u, ".eE"):
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, p.errorf(m, ErrNumber)
+		}
+		return f, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil, p.errorf(m, ErrNumber)
+	}
+	return n, nil
+}
+
+// #endregion Number
+
+// #region DateTime
+
+// LocalDate is a date without a time zone.
+type LocalDate struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+func (d LocalDate) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// LocalTime is a time of day without a time zone.
+type LocalTime struct {
+	Hour, Minute, Second int
+	Nanosecond           int
+}
+
+func (t LocalTime) String() string {
+	s := fmt.Sprintf("%02d:%02d:%02d", t.Hour, t.Minute, t.Second)
+	if t.Nanosecond > 0 {
+		s += strings.TrimRight(fmt.Sprintf(".%09d", t.Nanosecond), "0")
+	}
+	return s
+}
+
+// LocalDateTime is a date-time without a time zone.
+type LocalDateTime struct {
+	Date LocalDate
+	Time LocalTime
+}
+
+func (dt LocalDateTime) String() string {
+	return dt.Date.String() + "T" + dt.Time.String()
+}
+
+// In returns the date-time in a location.
+func (dt LocalDateTime) In(loc *time.Location) time.Time {
+	d, t := dt.Date, dt.Time
+	return time.Date(d.Year, d.Month, d.Day, t.Hour, t.Minute, t.Second, t.Nanosecond, loc)
+}
+
+// isDateTime tells if s starts with a date, like
+// 1979-05-27, or with a time, like 07:32:00.
+func isDateTime(s scanner.Scanner) bool {
+	return len(s) > 4 && digits(s[:4]) && s[4] == '-' ||
+		len(s) > 2 && digits(s[:2]) && s[2] == ':'
+}
+
+// datetime decodes a date-time, date or time.
+func (p *parser) datetime() (any, error) {
+	m := p.s
+	if p.s[2] == ':' {
+		t, ok := p.time()
+		if !ok || p.end(m) != nil {
+			return nil, p.errorf(m, ErrDate)
+		}
+		return t, nil
+	}
+	d, ok := p.date()
+	if !ok {
+		return nil, p.errorf(m, ErrDate)
+	}
+	// The separator can be a space if a time follows.
+	if c := p.s.Curr(); p.s.More() && (c == 'T' || c == 't' || c == ' ' && len(p.s) > 3 && digits(p.s[1:3]) && p.s[3] == ':') {
+		p.s.Next()
+		t, ok := p.time()
+		if !ok {
+			return nil, p.errorf(m, ErrDate)
+		}
+		dt := LocalDateTime{Date: d, Time: t}
+		loc, ok := p.offset()
+		if !ok || p.end(m) != nil {
+			return nil, p.errorf(m, ErrDate)
+		}
+		if loc == nil {
+			return dt, nil
+		}
+		return dt.In(loc), nil
+	}
+	if p.end(m) != nil {
+		return nil, p.errorf(m, ErrDate)
+	}
+	return d, nil
+}
+
+// date decodes a date, like 1979-05-27.
+func (p *parser) date() (LocalDate, bool) {
+	s := p.s
+	if len(s) < 10 || !digits(s[:4]) || s[4] != '-' || !digits(s[5:7]) || s[7] != '-' || !digits(s[8:10]) {
+		return LocalDate{}, false
+	}
+	d := LocalDate{Year: atoi(s[:4]), Month: time.Month(atoi(s[5:7])), Day: atoi(s[8:10])}
+	// Dates like 02-30 normalize to other days.
+	t := time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, time.UTC)
+	if t.Year() != d.Year || t.Month() != d.Month || t.Day() != d.Day {
+		return LocalDate{}, false
+	}
+	p.s.Advance(10)
+	return d, true
+}
+
+// time decodes a time, like 07:32:00.999.
+func (p *parser) time() (LocalTime, bool) {
+	s := p.s
+	if len(s) < 8 || !digits(s[:2]) || s[2] != ':' || !digits(s[3:5]) || s[5] != ':' || !digits(s[6:8]) {
+		return LocalTime{}, false
+	}
+	t := LocalTime{Hour: atoi(s[:2]), Minute: atoi(s[3:5]), Second: atoi(s[6:8])}
+	if t.Hour > 23 || t.Minute > 59 || t.Second > 60 {
+		return LocalTime{}, false
+	}
+	p.s.Advance(8)
+	if p.s.MatchByte('.') {
+		m := p.s
+		if !p.s.MatchWhileByteBy(isDigit) {
+			return LocalTime{}, false
+		}
+		// Digits past nanoseconds are truncated.
+		frac := p.s.Token(m) + "000000000"
+		t.Nanosecond = atoi(scanner.Scanner(frac[:9]))
+	}
+	return t, true
+}
+
+// offset decodes a time zone offset, like Z or -07:00.
+// Returns nil if there is none.
+func (p *parser) offset() (*time.Location, bool) {
+	if p.s.MatchByte('Z') || p.s.MatchByte('z') {
+		return time.UTC, true
+	}
+	c := p.s.Curr()
+	if !p.s.More() || c != '+' && c != '-' {
+		return nil, true
+	}
+	s := p.s[1:]
+	if len(s) < 5 || !digits(s[:2]) || s[2] != ':' || !digits(s[3:5]) {
+		return nil, false
+	}
+	h, m := atoi(s[:2]), atoi(s[3:5])
+	if h > 23 || m > 59 {
+		return nil, false
+	}
+	off := (h*60 + m) * 60
+	if c == '-' {
+		off = -off
+	}
+	p.s.Advance(6)
+	return time.FixedZone("", off), true
+}
+
+func digits(s scanner.Scanner) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func atoi(s scanner.Scanner) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		n = n*10 + int(s[i]-'0')
+	}
+	return n
+}
+
+// #endregion DateTime
@@ -0,0 +1,107 @@
+package toml
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+// #region Value
+
+func TestDecodeValue(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  any
+	}{
+		// Strings.
+		{give: `"a\tb \"q\" \\ \u00e9 \U0001F600"`, exp: "a\tb \"q\" \\ é 😀"},
+		{give: `'C:\Users\#x'`, exp: `C:\Users\#x`},
+		{give: "\"\"\"\nline1\nline2\"\"\"", exp: "line1\nline2"},
+		{give: "\"\"\"\r\nthe \\\n    quick \\\n\n  fox\"\"\"", exp: "the quick fox"},
+		{give: `"""a "b" ""c"""""`, exp: `a "b" ""c""`},
+		{give: "'''\n\\n raw '' '''", exp: `\n raw '' `},
+		{give: `''''x''''`, exp: `'x'`},
+		// Integers.
+		{give: "+99", exp: int64(99)},
+		{give: "-17", exp: int64(-17)},
+		{give: "1_000", exp: int64(1000)},
+		{give: "0xDEAD_beef", exp: int64(0xdeadbeef)},
+		{give: "0o755", exp: int64(0755)},
+		{give: "0b1101", exp: int64(13)},
+		// Floats.
+		{give: "3.1415", exp: 3.1415},
+		{give: "-0.01", exp: -0.01},
+		{give: "5e+22", exp: 5e+22},
+		{give: "6.626e-34", exp: 6.626e-34},
+		{give: "224_617.445_991", exp: 224617.445991},
+		{give: "inf", exp: math.Inf(1)},
+		{give: "-inf", exp: math.Inf(-1)},
+		// Booleans.
+		{give: "true", exp: true},
+		{give: "false", exp: false},
+		// Date-times.
+		{give: "1979-05-27T07:32:00Z", exp: time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC)},
+		{give: "1979-05-27 00:32:00.999999-07:00", exp: time.Date(1979, 5, 27, 0, 32, 0, 999999000, time.FixedZone("", -7*3600))},
+		{give: "1979-05-27t07:32:00", exp: LocalDateTime{LocalDate{1979, 5, 27}, LocalTime{7, 32, 0, 0}}},
+		{give: "1979-05-27", exp: LocalDate{1979, 5, 27}},
+		{give: "00:32:00.1234567891", exp: LocalTime{0, 32, 0, 123456789}},
+		// Arrays.
+		{give: "[ 1, 'a', [2.0], { x = 1 } ]", exp: []any{int64(1), "a", []any{2.0}, map[string]any{"x": int64(1)}}},
+		{give: "[\n# c\n]", exp: []any{}},
+	}
+	for _, tc := range tt {
+		got, err := Decode("v = " + tc.give)
+		assertEqual(t, nil, err, tc.give)
+		assertEqual(t, tc.exp, got["v"], tc.give)
+	}
+	got, err := Decode("v = nan")
+	assertEqual(t, nil, err)
+	assertEqual(t, true, math.IsNaN(got["v"].(float64)))
+}
+
+func TestDecodeValueError(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+		err  error
+	}{
+		{give: `"open`, exp: "toml: unclosed string at 1:5", err: ErrString},
+		{give: "'open\n'", exp: "toml: unclosed string at 1:5", err: ErrString},
+		{give: `"""open""`, exp: "toml: unclosed string at 1:5", err: ErrString},
+		{give: `"a\qb"`, exp: "toml: invalid escape at 1:7", err: ErrEscape},
+		{give: `"\uD800"`, exp: "toml: invalid escape at 1:6", err: ErrEscape},
+		{give: `"\u12"`, exp: "toml: invalid escape at 1:6", err: ErrEscape},
+		{give: "9223372036854775808", exp: "toml: invalid number at 1:5", err: ErrNumber},
+		{give: "1.5x", exp: "toml: invalid number at 1:5", err: ErrNumber},
+		{give: "1979-02-30", exp: "toml: invalid date-time at 1:5", err: ErrDate},
+		{give: "1979-05-27T25:00:00", exp: "toml: invalid date-time at 1:5", err: ErrDate},
+		{give: "1979-05-27T07:32", exp: "toml: invalid date-time at 1:5", err: ErrDate},
+		{give: "07:32:00+01:00", exp: "toml: invalid date-time at 1:5", err: ErrDate},
+		{give: "truex", exp: "toml: invalid value at 1:5", err: ErrValue},
+		{give: "yes", exp: "toml: invalid value at 1:5", err: ErrValue},
+	}
+	for _, tc := range tt {
+		_, err := Decode("v = " + tc.give)
+		if assertEqual(t, true, err != nil, tc.give) {
+			assertEqual(t, tc.exp, err.Error(), tc.give)
+			assertEqual(t, true, errors.Is(err, tc.err), tc.give)
+		}
+	}
+}
+
+func TestLocalString(t *testing.T) {
+	dt := LocalDateTime{LocalDate{1979, 5, 27}, LocalTime{7, 32, 0, 500000000}}
+	assertEqual(t, "1979-05-27T07:32:00.5", dt.String())
+	assertEqual(t, "07:32:00", LocalTime{7, 32, 0, 0}.String())
+	assertEqual(t, time.Date(1979, 5, 27, 7, 32, 0, 500000000, time.UTC), dt.In(time.UTC))
+}
+
+func BenchmarkDecodeValue(b *testing.B) {
+	src := "a = \"x\\ty\"\nb = 'lit'\nc = 1_000\nd = 3.14\ne = 1979-05-27T07:32:00Z\nf = [1, 2, 3]\ng = { x = 1 }\n"
+	for i := 0; i < b.N; i++ {
+		Decode(src)
+	}
+}
+
+// #endregion Value
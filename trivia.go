@@ -0,0 +1,152 @@
+package scanner
+
+// #region Trivia
+
+// Pair is a pair of open and close delimiters.
+type Pair struct {
+	Open, Close string
+}
+
+// Trivia describes what is skipped as whitespace.
+type Trivia struct {
+	// Line comments, like "//", "#" and "--".
+	// They end before the line break.
+	Line []string
+	// Block comments, like {"/*", "*/"}.
+	Block []Pair
+	// Nested tells if block comments nest,
+	// like {- outer {- inner -} -} in Haskell.
+	// Empty delimiters are ignored.
+	Nested bool
+}
+
+// Trivia of some languages.
+var (
+	TriviaC = Trivia{
+		Line:  []string{"//"},
+		Block: []Pair{{"/*", "*/"}},
+	}
+	TriviaShell = Trivia{
+		Line: []string{"#"},
+	}
+	TriviaSQL = Trivia{
+		Line:  []string{"--"},
+		Block: []Pair{{"/*", "*/"}},
+	}
+	TriviaHaskell = Trivia{
+		Line:   []string{"--"},
+		Block:  []Pair{{"{-", "-}"}},
+		Nested: true,
+	}
+)
+
+// SkipTrivia skips whitespaces and comments.
+// Returns false on an unclosed block comment,
+// leaving the scanner at the comment start.
+func (s *Scanner) SkipTrivia(t *Trivia) bool {
+	return s.SkipTriviaFunc(t, nil)
+}
+
+// SkipTriviaFunc is like SkipTrivia but calls f
+// with each comment skipped, delimiters included.
+func (s *Scanner) SkipTriviaFunc(t *Trivia, f func(comment string)) bool {
+	ss := *s
+	for len(ss) > 0 {
+		if ss[0] <= ' ' {
+			ss = ss[1:]
+			continue
+		}
+		if t.line(ss) {
+			ss.skipComment(f, ss.matchLine())
+			continue
+		}
+		if p, ok := t.block(ss); ok {
+			n, ok := ss.matchBlock(p, t.Nested)
+			if !ok {
+				*s = ss
+				return false
+			}
+			ss.skipComment(f, n)
+			continue
+		}
+		break
+	}
+	*s = ss
+	return true
+}
+
+// SkipShebang skips a "#!" line, like "#!/bin/sh".
+// A scanner doesn't know where the input starts,
+// so call it only there.
+func (s *Scanner) SkipShebang() bool {
+	if !s.Equal("#!") {
+		return false
+	}
+	s.Advance(s.matchLine())
+	return true
+}
+
+// skipComment skips n bytes and calls f with them.
+func (s *Scanner) skipComment(f func(string), n int) {
+	if f != nil {
+		f((*s)[:n].String())
+	}
+	*s = (*s)[n:]
+}
+
+// line tells if a line comment starts at ss.
+func (t *Trivia) line(ss Scanner) bool {
+	for _, v := range t.Line {
+		if v != "" && ss.Equal(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// block returns the block comment that starts at ss.
+func (t *Trivia) block(ss Scanner) (Pair, bool) {
+	for _, p := range t.Block {
+		if p.Open != "" && ss.Equal(p.Open) {
+			return p, true
+		}
+	}
+	return Pair{}, false
+}
+
+// matchLine returns the length up to the line break or EOF.
+func (s Scanner) matchLine() int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\n' {
+			if i > 0 && s[i-1] == '\r' {
+				return i - 1
+			}
+			return i
+		}
+	}
+	return len(s)
+}
+
+// matchBlock returns the length of the
+// block comment p that starts at s.
+func (s Scanner) matchBlock(p Pair, nested bool) (int, bool) {
+	depth := 0
+	for i := 0; i < len(s); {
+		if s[i:].Equal(p.Open) && (nested || depth == 0) {
+			depth++
+			i += len(p.Open)
+			continue
+		}
+		if s[i:].Equal(p.Close) {
+			i += len(p.Close)
+			if depth--; depth == 0 {
+				return i, true
+			}
+			continue
+		}
+		i++
+	}
+	return 0, false
+}
+
+// #endregion Trivia
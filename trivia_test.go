@@ -0,0 +1,73 @@
+package scanner
+
+import "testing"
+
+// #region Trivia
+
+func TestScannerSkipTrivia(t *testing.T) {
+	tt := []struct {
+		give string
+		when Trivia
+		then bool
+		exp  string
+	}{
+		{give: " \t\r\n.", when: TriviaC, then: true, exp: `.`},
+		{give: "// a\n// b\r\n.", when: TriviaC, then: true, exp: `.`},
+		{give: "/* a */ /* b\n */.", when: TriviaC, then: true, exp: `.`},
+		{give: "/* /* a */ */", when: TriviaC, then: true, exp: `*/`},
+		{give: "// a", when: TriviaC, then: true, exp: ``},
+		{give: " /* a", when: TriviaC, then: false, exp: `/* a`},
+		{give: "/ .", when: TriviaC, then: true, exp: `/ .`},
+		{give: "#!/bin/sh\n# a\necho", when: TriviaShell, then: true, exp: `echo`},
+		{give: "-- a\n/* b */SELECT", when: TriviaSQL, then: true, exp: `SELECT`},
+		{give: "{- {- a -} -} x", when: TriviaHaskell, then: true, exp: `x`},
+		{give: "{- {- a -} x", when: TriviaHaskell, then: false, exp: `{- {- a -} x`},
+		{give: "# a\n.", when: TriviaC, then: true, exp: "# a\n."},
+		{give: "", when: TriviaC, then: true, exp: ``},
+		{give: "a", when: Trivia{Line: []string{""}, Block: []Pair{{"", "*/"}}}, then: true, exp: `a`},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		assertEqual(t, tc.then, s.SkipTrivia(&tc.when), tc.give)
+		assertEqual(t, tc.exp, s.String(), tc.give)
+	}
+}
+
+func BenchmarkScannerSkipTrivia(b *testing.B) {
+	x := Scanner("  // a\n  /* b */ .")
+	for i := 0; i < b.N; i++ {
+		s := x
+		s.SkipTrivia(&TriviaC)
+	}
+}
+
+func TestScannerSkipShebang(t *testing.T) {
+	tt := []struct {
+		give string
+		then bool
+		exp  string
+	}{
+		{give: "#!/bin/sh\r\necho", then: true, exp: "\r\necho"},
+		{give: "#!", then: true, exp: ``},
+		{give: " #!/bin/sh", then: false, exp: ` #!/bin/sh`},
+		{give: "# a", then: false, exp: `# a`},
+	}
+	for _, tc := range tt {
+		s := Scanner(tc.give)
+		assertEqual(t, tc.then, s.SkipShebang(), tc.give)
+		assertEqual(t, tc.exp, s.String(), tc.give)
+	}
+}
+
+func TestScannerSkipTriviaFunc(t *testing.T) {
+	var got []string
+	s := Scanner("#!/bin/sh\n  # one\r\n\n# two\necho")
+	ok := s.SkipTriviaFunc(&TriviaShell, func(c string) {
+		got = append(got, c)
+	})
+	assertEqual(t, true, ok)
+	assertEqual(t, []string{"#!/bin/sh", "# one", "# two"}, got)
+	assertEqual(t, `echo`, s.String())
+}
+
+// #endregion Trivia
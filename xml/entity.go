@@ -0,0 +1,105 @@
+package xml
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ofabricio/scanner"
+)
+
+// #region Entity
+
+// entities are the predefined entities. Entities
+// declared in a DOCTYPE are not supported.
+var entities = map[string]byte{
+	"lt": '<', "gt": '>', "amp": '&', "apos": '\'', "quot": '"',
+}
+
+// decode decodes the references of raw, which is at
+// at, and normalizes its line breaks to '\n'. In an
+// attribute value whitespaces are normalized to ' '.
+func (t *Tokenizer) decode(raw string, at scanner.Scanner, attr bool) (string, error) {
+	special := "&\r"
+	if attr {
+		special = "&\r\n\t"
+	}
+	if !strings.ContainsAny(raw, special) {
+		return raw, nil
+	}
+	b := make([]byte, 0, len(raw))
+	for i := 0; i < len(raw); {
+		switch c := raw[i]; {
+		case c == '&':
+			var n int
+			var key string
+			var err error
+			if b, n, key, err = reference(b, raw[i:]); err != nil {
+				return "", t.errorKey(at[i:], err, key)
+			}
+			i += n
+		case c == '\r' && strings.HasPrefix(raw[i+1:], "\n"):
+			i++ // A \r\n is one line break.
+		case attr && (c == '\t' || c == '\n' || c == '\r'):
+			b = append(b, ' ')
+			i++
+		case c == '\r':
+			b = append(b, '\n')
+			i++
+		default:
+			b = append(b, c)
+			i++
+		}
+	}
+	return string(b), nil
+}
+
+// reference decodes the reference at the start of s, like
+// &amp; or &#x41;, into b. It returns the length of the
+// reference, or an error and the name of the entity.
+func reference(b []byte, s string) ([]byte, int, string, error) {
+	end := strings.IndexByte(s, ';')
+	if strings.HasPrefix(s, "&#") {
+		if end < 0 {
+			return b, 0, "", ErrCharRef
+		}
+		r, ok := charRef(s[2:end])
+		if !ok || !isChar(r) {
+			return b, 0, "", ErrCharRef
+		}
+		return utf8.AppendRune(b, r), end + 1, "", nil
+	}
+	m := scanner.Scanner(s[1:])
+	m.UtilMatchIdent(nameIdent)
+	name := s[1 : len(s)-len(m)]
+	if !m.EqualByte(';') {
+		return b, 0, name, ErrEntity
+	}
+	c, ok := entities[name]
+	if !ok {
+		return b, 0, name, ErrEntity
+	}
+	return append(b, c), len(name) + 2, "", nil
+}
+
+// charRef decodes the digits of a character
+// reference, like 65 or x41.
+func charRef(digits string) (rune, bool) {
+	if strings.HasPrefix(digits, "x") {
+		// Leading zeros may exceed the 8 digits.
+		s := scanner.Scanner(strings.TrimLeft(digits[1:], "0"))
+		return s.UtilParseHexRune(len(s))
+	}
+	v, err := strconv.ParseUint(digits, 10, 32)
+	return rune(v), err == nil && !strings.ContainsAny(digits, "+-_")
+}
+
+// isChar tells if r is a character XML allows.
+func isChar(r rune) bool {
+	return r == '\t' || r == '\n' || r == '\r' ||
+		r >= 0x20 && r <= 0xD7FF ||
+		r >= 0xE000 && r <= 0xFFFD ||
+		r >= 0x10000 && r <= 0x10FFFF
+}
+
+// #endregion Entity
@@ -0,0 +1,88 @@
+package xml
+
+import (
+	"errors"
+	"testing"
+)
+
+// #region Entity
+
+func TestEntityText(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+	}{
+		{give: "a &lt; b &amp;&amp; c &gt; d", exp: "a < b && c > d"},
+		{give: "&apos;&quot;", exp: `'"`},
+		{give: "&#65;&#x42;&#x1F600;", exp: "AB😀"},
+		{give: "&#x0000000041;&#0065;", exp: "AA"},
+		{give: "a\r\nb\rc", exp: "a\nb\nc"},
+		{give: "a\tb\nc", exp: "a\tb\nc"},
+		{give: "plain", exp: "plain"},
+	}
+	for _, tc := range tt {
+		z := NewTokenizer("<a>" + tc.give + "</a>")
+		z.Next()
+		tok, err := z.Next()
+		assertEqual(t, nil, err, tc.give)
+		assertEqual(t, Text, tok.Kind, tc.give)
+		assertEqual(t, tc.exp, tok.Data, tc.give)
+	}
+}
+
+func TestEntityAttr(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+	}{
+		{give: "a&amp;b", exp: "a&b"},
+		{give: "a\tb\nc\r\nd", exp: "a b c d"},
+		{give: "a&#10;b&#9;c", exp: "a\nb\tc"},
+		{give: "&lt;&#x3C;", exp: "<<"},
+	}
+	for _, tc := range tt {
+		z := NewTokenizer(`<a b="` + tc.give + `"/>`)
+		tok, err := z.Next()
+		assertEqual(t, nil, err, tc.give)
+		assertEqual(t, tc.exp, tok.Attrs[0].Value, tc.give)
+	}
+}
+
+func TestEntityError(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+		err  error
+	}{
+		{give: "<a>&nbsp;</a>", exp: `xml: invalid entity reference "nbsp" at 1:4`, err: ErrEntity},
+		{give: "<a>x &amp</a>", exp: `xml: invalid entity reference "amp" at 1:6`, err: ErrEntity},
+		{give: "<a>a & b</a>", exp: "xml: invalid entity reference at 1:6", err: ErrEntity},
+		{give: "<a>&#;</a>", exp: "xml: invalid character reference at 1:4", err: ErrCharRef},
+		{give: "<a>&#0;</a>", exp: "xml: invalid character reference at 1:4", err: ErrCharRef},
+		{give: "<a>&#xD800;</a>", exp: "xml: invalid character reference at 1:4", err: ErrCharRef},
+		{give: "<a>&#x110000;</a>", exp: "xml: invalid character reference at 1:4", err: ErrCharRef},
+		{give: "<a>&#+65;</a>", exp: "xml: invalid character reference at 1:4", err: ErrCharRef},
+		{give: "<a>&#65</a>", exp: "xml: invalid character reference at 1:4", err: ErrCharRef},
+		{give: "<a>&#x0;</a>", exp: "xml: invalid character reference at 1:4", err: ErrCharRef},
+		{give: "<a>&#xG;</a>", exp: "xml: invalid character reference at 1:4", err: ErrCharRef},
+		{give: "<a\n b='&x;'/>", exp: `xml: invalid entity reference "x" at 2:5`, err: ErrEntity},
+	}
+	for _, tc := range tt {
+		_, err := tokens(NewTokenizer(tc.give))
+		if assertEqual(t, true, err != nil, tc.give) {
+			assertEqual(t, tc.exp, err.Error(), tc.give)
+			assertEqual(t, true, errors.Is(err, tc.err), tc.give)
+		}
+	}
+}
+
+func BenchmarkEntity(b *testing.B) {
+	src := "<a>Tom &amp; Jerry &lt;3 &#x1F600; &#169; &quot;cartoons&quot;</a>"
+	for i := 0; i < b.N; i++ {
+		z := NewTokenizer(src)
+		z.Next()
+		z.Next()
+	}
+}
+
+// #endregion Entity
@@ -0,0 +1,545 @@
+// Package xml is a pull tokenizer of XML documents on
+// the scanner. It checks that documents are well-formed,
+// decodes character references and can resolve namespaces.
+// Text with no references is a sub-slice of the input,
+// so reading it doesn't allocate.
+package xml
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ofabricio/scanner"
+)
+
+// #region Errors
+
+var (
+	ErrName      = errors.New("expected a name")
+	ErrTag       = errors.New("malformed tag")
+	ErrAttr      = errors.New("expected '=' and a quoted value")
+	ErrLT        = errors.New("'<' in attribute value")
+	ErrDuplicate = errors.New("duplicate attribute")
+	ErrMismatch  = errors.New("mismatched end tag")
+	ErrUnclosed  = errors.New("unclosed element")
+	ErrEOF       = errors.New("unexpected end of input")
+	ErrDashes    = errors.New("'--' in comment")
+	ErrCDATAEnd  = errors.New("']]>' in text")
+	ErrEntity    = errors.New("invalid entity reference")
+	ErrCharRef   = errors.New("invalid character reference")
+	ErrContent   = errors.New("content outside the root element")
+	ErrRoot      = errors.New("multiple root elements")
+	ErrNoRoot    = errors.New("missing root element")
+	ErrDecl      = errors.New("XML declaration not at the start")
+	ErrDoctype   = errors.New("misplaced DOCTYPE")
+	ErrPrefix    = errors.New("unbound namespace prefix")
+)
+
+// ParseError is a positioned error.
+// Key is the name of some errors, like
+// ErrMismatch, ErrDuplicate and ErrEntity.
+type ParseError = scanner.ParseError
+
+// #endregion Errors
+
+// #region Token
+
+// Kind is the kind of a Token.
+type Kind int
+
+const (
+	StartElement Kind = iota + 1
+	EndElement
+	Text
+	CDATA
+	Comment
+	ProcInst
+	Doctype
+)
+
+func (k Kind) String() string {
+	switch k {
+	case StartElement:
+		return "StartElement"
+	case EndElement:
+		return "EndElement"
+	case Text:
+		return "Text"
+	case CDATA:
+		return "CDATA"
+	case Comment:
+		return "Comment"
+	case ProcInst:
+		return "ProcInst"
+	case Doctype:
+		return "Doctype"
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Name is a qualified name, like ns:local.
+type Name struct {
+	// Space is the prefix of the name, or its
+	// URI if the tokenizer resolves namespaces.
+	Space string
+	Local string
+}
+
+// Attr is an attribute of a start element.
+type Attr struct {
+	Name Name
+	// Value is the value with references decoded
+	// and whitespaces normalized to spaces.
+	Value string
+	Span  scanner.Span
+}
+
+// Token is a token of a document.
+type Token struct {
+	Kind Kind
+	// Name is the name of an element or
+	// the target of a processing instruction.
+	Name Name
+	// Attrs are the attributes of a start element.
+	// The slice is reused by the next call.
+	Attrs []Attr
+	// Data is the decoded text of a Text, the content of
+	// a CDATA, Comment or ProcInst and the declaration
+	// of a Doctype, like `html PUBLIC "-//W3C//DTD"`.
+	Data string
+	// SelfClosing tells if a start element is
+	// like <a/>. Its EndElement comes next.
+	SelfClosing bool
+	Span        scanner.Span
+}
+
+// #endregion Token
+
+// #region Tokenizer
+
+// Tokenizer reads the tokens of an XML document.
+type Tokenizer struct {
+	// Namespaces resolves the prefixes of names
+	// into the URIs of the xmlns attributes in
+	// scope. Unprefixed attributes have no space.
+	Namespaces bool
+
+	src, s  scanner.Scanner
+	stack   []elem
+	ns      []binding
+	attrs   []Attr
+	root    bool // The root element was read.
+	doctype bool // The DOCTYPE was read.
+	end     bool // A self-closing element is pending its end.
+	err     error
+}
+
+// elem is an open element.
+type elem struct {
+	raw  string
+	name Name
+	ns   int // Bindings before the element.
+	at   scanner.Scanner
+}
+
+// binding binds a prefix to a namespace URI.
+type binding struct {
+	prefix, uri string
+}
+
+// xmlURI is the namespace of the xml prefix.
+const xmlURI = "http://www.w3.org/XML/1998/namespace"
+
+// NewTokenizer returns a tokenizer of src.
+func NewTokenizer(src string) *Tokenizer {
+	s := scanner.Scanner(src)
+	return &Tokenizer{src: s, s: s}
+}
+
+// Next returns the next token, or io.EOF at the end
+// of a document with a root element.
+// Whitespace outside the root element is skipped.
+// Errors are final: Next keeps returning them.
+func (t *Tokenizer) Next() (Token, error) {
+	if t.err != nil {
+		return Token{}, t.err
+	}
+	tok, err := t.next()
+	if err != nil {
+		t.err = err
+	}
+	return tok, err
+}
+
+func (t *Tokenizer) next() (Token, error) {
+	if t.end {
+		t.end = false
+		return t.pop(t.s), nil
+	}
+	for {
+		switch {
+		case !t.s.More():
+			if n := len(t.stack); n > 0 {
+				e := t.stack[n-1]
+				return Token{}, t.errorKey(e.at, ErrUnclosed, e.raw)
+			}
+			if !t.root {
+				return Token{}, t.errorf(t.s, ErrNoRoot)
+			}
+			return Token{}, io.EOF
+		case t.s.Equal("<!--"):
+			return t.comment()
+		case t.s.Equal("<![CDATA["):
+			return t.cdata()
+		case t.s.Equal("<!DOCTYPE"):
+			return t.doctypeDecl()
+		case t.s.Equal("<?"):
+			return t.procInst()
+		case t.s.Equal("</"):
+			return t.endElement()
+		case t.s.EqualByte('<'):
+			return t.startElement()
+		}
+		at := t.s
+		tok, err := t.text()
+		if err != nil || len(t.stack) > 0 {
+			return tok, err
+		}
+		raw := t.s.Token(at)
+		if v := strings.TrimLeft(raw, " \t\r\n"); v != "" {
+			return Token{}, t.errorf(at[len(raw)-len(v):], ErrContent)
+		}
+	}
+}
+
+// startElement reads a start tag, like <a b="c">.
+func (t *Tokenizer) startElement() (Token, error) {
+	at := t.s
+	t.s.Next()
+	raw, err := t.name()
+	if err != nil {
+		return Token{}, err
+	}
+	if len(t.stack) == 0 {
+		if t.root {
+			return Token{}, t.errorf(at, ErrRoot)
+		}
+		t.root = true
+	}
+	t.attrs = t.attrs[:0]
+	closing := false
+	for {
+		ws := t.ws()
+		if t.s.Match("/>") {
+			closing = true
+			break
+		}
+		if t.s.MatchByte('>') {
+			break
+		}
+		if !t.s.More() {
+			return Token{}, t.errorf(at, ErrEOF)
+		}
+		if !ws {
+			return Token{}, t.errorf(t.s, ErrTag)
+		}
+		if err := t.attr(); err != nil {
+			return Token{}, err
+		}
+	}
+	e := elem{raw: raw, name: split(raw), ns: len(t.ns), at: at}
+	if t.Namespaces {
+		if err := t.resolve(&e); err != nil {
+			return Token{}, err
+		}
+	}
+	t.stack = append(t.stack, e)
+	t.end = closing
+	return Token{Kind: StartElement, Name: e.name, Attrs: t.attrs, SelfClosing: closing, Span: t.src.Span(at, t.s)}, nil
+}
+
+// attr reads an attribute, like b="c".
+func (t *Tokenizer) attr() error {
+	at := t.s
+	raw, err := t.name()
+	if err != nil {
+		return err
+	}
+	name := split(raw)
+	for _, a := range t.attrs {
+		if a.Name == name {
+			return t.errorKey(at, ErrDuplicate, raw)
+		}
+	}
+	t.ws()
+	if !t.s.MatchByte('=') {
+		return t.errorf(t.s, ErrAttr)
+	}
+	t.ws()
+	m := t.s
+	v, err := t.quoted()
+	if err != nil {
+		return err
+	}
+	if i := strings.IndexByte(v, '<'); i >= 0 {
+		return t.errorf(m[1+i:], ErrLT)
+	}
+	if v, err = t.decode(v, m[1:], true); err != nil {
+		return err
+	}
+	t.attrs = append(t.attrs, Attr{Name: name, Value: v, Span: t.src.Span(at, t.s)})
+	return nil
+}
+
+// quoted reads a value quoted by " or '. Values have
+// no escapes, so the value ends at its first quote.
+func (t *Tokenizer) quoted() (string, error) {
+	m := t.s
+	q := t.s.Curr()
+	if q != '"' && q != '\'' {
+		return "", t.errorf(m, ErrAttr)
+	}
+	t.s.Next()
+	if !t.s.MatchUntilByte(q) {
+		return "", t.errorf(m, ErrEOF)
+	}
+	v := t.s.Token(m[1:])
+	t.s.Next()
+	return v, nil
+}
+
+// resolve binds the xmlns attributes of an element
+// and resolves its name and the names of its attributes.
+func (t *Tokenizer) resolve(e *elem) error {
+	for _, a := range t.attrs {
+		if a.Name.Space == "" && a.Name.Local == "xmlns" {
+			t.ns = append(t.ns, binding{"", a.Value})
+		} else if a.Name.Space == "xmlns" {
+			t.ns = append(t.ns, binding{a.Name.Local, a.Value})
+		}
+	}
+	uri, ok := t.lookup(e.name.Space)
+	if !ok {
+		return t.errorKey(e.at[1:], ErrPrefix, e.name.Space)
+	}
+	e.name.Space = uri
+	for i := range t.attrs {
+		a := &t.attrs[i]
+		if a.Name.Space == "" || a.Name.Space == "xmlns" {
+			continue
+		}
+		uri, ok := t.lookup(a.Name.Space)
+		if !ok {
+			return t.errorKey(t.src[a.Span.Start:], ErrPrefix, a.Name.Space)
+		}
+		a.Name.Space = uri
+		for _, b := range t.attrs[:i] {
+			if b.Name == a.Name {
+				return t.errorKey(t.src[a.Span.Start:], ErrDuplicate, a.Name.Local)
+			}
+		}
+	}
+	return nil
+}
+
+// lookup returns the URI of a prefix in scope.
+func (t *Tokenizer) lookup(prefix string) (string, bool) {
+	if prefix == "xml" {
+		return xmlURI, true
+	}
+	for i := len(t.ns) - 1; i >= 0; i-- {
+		if t.ns[i].prefix == prefix {
+			return t.ns[i].uri, true
+		}
+	}
+	return "", prefix == ""
+}
+
+// endElement reads an end tag, like </a>.
+func (t *Tokenizer) endElement() (Token, error) {
+	at := t.s
+	t.s.Advance(2)
+	raw, err := t.name()
+	if err != nil {
+		return Token{}, err
+	}
+	t.ws()
+	if !t.s.MatchByte('>') {
+		return Token{}, t.errorf(t.s, ErrTag)
+	}
+	if n := len(t.stack); n == 0 || t.stack[n-1].raw != raw {
+		return Token{}, t.errorKey(at, ErrMismatch, raw)
+	}
+	return t.pop(at), nil
+}
+
+// pop closes the open element.
+func (t *Tokenizer) pop(at scanner.Scanner) Token {
+	e := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+	t.ns = t.ns[:e.ns]
+	return Token{Kind: EndElement, Name: e.name, Span: t.src.Span(at, t.s)}
+}
+
+// text reads text up to a tag.
+func (t *Tokenizer) text() (Token, error) {
+	at := t.s
+	if !t.s.MatchUntilByte('<') {
+		t.s.Advance(len(t.s))
+	}
+	raw := t.s.Token(at)
+	if i := strings.Index(raw, "]]>"); i >= 0 {
+		return Token{}, t.errorf(at[i:], ErrCDATAEnd)
+	}
+	v, err := t.decode(raw, at, false)
+	if err != nil {
+		return Token{}, err
+	}
+	return Token{Kind: Text, Data: v, Span: t.src.Span(at, t.s)}, nil
+}
+
+// cdata reads a CDATA section.
+func (t *Tokenizer) cdata() (Token, error) {
+	at := t.s
+	if len(t.stack) == 0 {
+		return Token{}, t.errorf(at, ErrContent)
+	}
+	t.s.Advance(len("<![CDATA["))
+	m := t.s
+	if !t.s.MatchUntil("]]>") {
+		return Token{}, t.errorf(at, ErrEOF)
+	}
+	v := t.s.Token(m)
+	t.s.Advance(3)
+	return Token{Kind: CDATA, Data: v, Span: t.src.Span(at, t.s)}, nil
+}
+
+// comment reads a comment. It can't have "--".
+func (t *Tokenizer) comment() (Token, error) {
+	at := t.s
+	t.s.Advance(4)
+	m := t.s
+	if !t.s.MatchUntil("--") {
+		return Token{}, t.errorf(at, ErrEOF)
+	}
+	v := t.s.Token(m)
+	if !t.s.Match("-->") {
+		return Token{}, t.errorf(t.s, ErrDashes)
+	}
+	return Token{Kind: Comment, Data: v, Span: t.src.Span(at, t.s)}, nil
+}
+
+// procInst reads a processing instruction,
+// like <?xml-stylesheet href="a.css"?>.
+func (t *Tokenizer) procInst() (Token, error) {
+	at := t.s
+	t.s.Advance(2)
+	target, err := t.name()
+	if err != nil {
+		return Token{}, err
+	}
+	if target == "xml" && len(at) != len(t.src) {
+		return Token{}, t.errorf(at, ErrDecl)
+	}
+	if !t.ws() && !t.s.Equal("?>") {
+		return Token{}, t.errorf(t.s, ErrTag)
+	}
+	m := t.s
+	if !t.s.MatchUntil("?>") {
+		return Token{}, t.errorf(at, ErrEOF)
+	}
+	v := t.s.Token(m)
+	t.s.Advance(2)
+	return Token{Kind: ProcInst, Name: Name{Local: target}, Data: v, Span: t.src.Span(at, t.s)}, nil
+}
+
+// doctypeDecl reads a DOCTYPE declaration. The
+// internal subset is skipped, not interpreted.
+func (t *Tokenizer) doctypeDecl() (Token, error) {
+	at := t.s
+	if t.root || t.doctype {
+		return Token{}, t.errorf(at, ErrDoctype)
+	}
+	t.doctype = true
+	t.s.Advance(len("<!DOCTYPE"))
+	if !t.ws() {
+		return Token{}, t.errorf(t.s, ErrTag)
+	}
+	m := t.s
+	for depth := 0; ; {
+		switch c := t.s.Curr(); {
+		case !t.s.More():
+			return Token{}, t.errorf(at, ErrEOF)
+		case c == '"' || c == '\'':
+			if _, err := t.quoted(); err != nil {
+				return Token{}, err
+			}
+			continue
+		case t.s.Equal("<!--"):
+			if !t.s.MatchUntil("-->") {
+				return Token{}, t.errorf(at, ErrEOF)
+			}
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == '>' && depth <= 0:
+			v := strings.TrimRight(t.s.Token(m), " \t\r\n")
+			t.s.Next()
+			return Token{Kind: Doctype, Data: v, Span: t.src.Span(at, t.s)}, nil
+		}
+		t.s.Next()
+	}
+}
+
+// nameIdent is an XML name. Its colon is
+// split into a prefix and a local name.
+var nameIdent = scanner.NewIdent(isNameStart, isNameChar)
+
+func isNameStart(r rune) bool {
+	return r == ':' || r == '_' || unicode.IsLetter(r)
+}
+
+func isNameChar(r rune) bool {
+	return isNameStart(r) || r == '-' || r == '.' || r == '·' ||
+		unicode.IsDigit(r) || unicode.In(r, unicode.Mn, unicode.Mc)
+}
+
+func (t *Tokenizer) name() (string, error) {
+	m := t.s
+	if !t.s.UtilMatchIdent(nameIdent) {
+		return "", t.errorf(m, ErrName)
+	}
+	return t.s.Token(m), nil
+}
+
+// split splits a name at its colon.
+func split(raw string) Name {
+	if i := strings.IndexByte(raw, ':'); i > 0 && i < len(raw)-1 {
+		return Name{Space: raw[:i], Local: raw[i+1:]}
+	}
+	return Name{Local: raw}
+}
+
+// ws skips whitespaces and tells if there were any.
+func (t *Tokenizer) ws() bool {
+	return t.s.MatchWhileByteBy(isSpace)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+func (t *Tokenizer) errorf(at scanner.Scanner, err error) error {
+	return t.errorKey(at, err, "")
+}
+
+func (t *Tokenizer) errorKey(at scanner.Scanner, err error, key string) error {
+	line, col := t.src.LineCol(len(t.src) - len(at))
+	return &ParseError{Line: line, Col: col, Err: err, Pkg: "xml", Key: key}
+}
+
+// #endregion Tokenizer
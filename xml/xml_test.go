@@ -0,0 +1,274 @@
+package xml
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// #region Tokenizer
+
+func TestTokenizer(t *testing.T) {
+	src := `<?xml version="1.0"?>
+<!DOCTYPE feed [<!ENTITY x "]>">]>
+<feed lang='en' id="1">
+  <!-- entries -->
+  <entry a = "x"/>
+  <?php echo 1; ?>
+  <data><![CDATA[<b>&amp;</b>]]></data>
+</feed>
+`
+	exp := []string{
+		`ProcInst xml "version=\"1.0\""`,
+		`Doctype "feed [<!ENTITY x \"]>\">]"`,
+		`StartElement feed [lang=en id=1]`,
+		`Text "\n  "`,
+		`Comment " entries "`,
+		`Text "\n  "`,
+		`StartElement entry [a=x] />`,
+		`EndElement entry`,
+		`Text "\n  "`,
+		`ProcInst php "echo 1; "`,
+		`Text "\n  "`,
+		`StartElement data []`,
+		`CDATA "<b>&amp;</b>"`,
+		`EndElement data`,
+		`Text "\n"`,
+		`EndElement feed`,
+	}
+	got, err := tokens(NewTokenizer(src))
+	assertEqual(t, nil, err)
+	assertEqual(t, exp, got)
+}
+
+func TestTokenizerSpan(t *testing.T) {
+	src := `<a x="1"><b/>t</a>`
+	z := NewTokenizer(src)
+	var got []string
+	for {
+		tok, err := z.Next()
+		if err != nil {
+			break
+		}
+		got = append(got, tok.Span.Text(src))
+		for _, a := range tok.Attrs {
+			got = append(got, a.Span.Text(src))
+		}
+	}
+	assertEqual(t, []string{`<a x="1">`, `x="1"`, `<b/>`, ``, `t`, `</a>`}, got)
+}
+
+func TestTokenizerQuotes(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+	}{
+		{give: `<a b="x'y" c='x"y'/>`, exp: `StartElement a [b=x'y c=x"y] />`},
+		{give: `<a b="C:\" c="d"/>`, exp: `StartElement a [b=C:\ c=d] />`},
+		{give: `<a b='\'/>`, exp: `StartElement a [b=\] />`},
+		{give: `<a b=""/>`, exp: `StartElement a [b=] />`},
+	}
+	for _, tc := range tt {
+		got, err := tokens(NewTokenizer(tc.give))
+		assertEqual(t, nil, err, tc.give)
+		assertEqual(t, tc.exp, got[0], tc.give)
+	}
+}
+
+func TestTokenizerNamespaces(t *testing.T) {
+	src := `<feed xmlns="urn:atom" xmlns:m="urn:media" xml:lang="en">` +
+		`<m:thumb m:url="a" url="b"/><entry xmlns=""/><m:x xmlns:m="urn:other"/></feed>`
+	exp := []string{
+		"StartElement {urn:atom}feed [xmlns=urn:atom xmlns:m=urn:media {http://www.w3.org/XML/1998/namespace}lang=en]",
+		"StartElement {urn:media}thumb [{urn:media}url=a url=b] />",
+		"EndElement {urn:media}thumb",
+		"StartElement entry [xmlns=] />",
+		"EndElement entry",
+		"StartElement {urn:other}x [xmlns:m=urn:other] />",
+		"EndElement {urn:other}x",
+		"EndElement {urn:atom}feed",
+	}
+	z := NewTokenizer(src)
+	z.Namespaces = true
+	got, err := tokens(z)
+	assertEqual(t, nil, err)
+	assertEqual(t, exp, got)
+
+	// Prefixes are kept if not resolving.
+	got, err = tokens(NewTokenizer(`<m:a m:b="1"/>`))
+	assertEqual(t, nil, err)
+	assertEqual(t, "StartElement m:a [m:b=1] />", got[0])
+}
+
+func TestTokenizerError(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+		err  error
+	}{
+		{give: "<a><b></a>", exp: `xml: mismatched end tag "a" at 1:7`, err: ErrMismatch},
+		{give: "</a>", exp: `xml: mismatched end tag "a" at 1:1`, err: ErrMismatch},
+		{give: "<a>\n  <b>", exp: `xml: unclosed element "b" at 2:3`, err: ErrUnclosed},
+		{give: "<a b='1' b='2'/>", exp: `xml: duplicate attribute "b" at 1:10`, err: ErrDuplicate},
+		{give: "<a b='1'c='2'/>", exp: "xml: malformed tag at 1:9", err: ErrTag},
+		{give: "<a></a x>", exp: "xml: malformed tag at 1:8", err: ErrTag},
+		{give: "<a b/>", exp: "xml: expected '=' and a quoted value at 1:5", err: ErrAttr},
+		{give: "<a b=1/>", exp: "xml: expected '=' and a quoted value at 1:6", err: ErrAttr},
+		{give: "<a b='1/>", exp: "xml: unexpected end of input at 1:6", err: ErrEOF},
+		{give: "<a b='<'/>", exp: "xml: '<' in attribute value at 1:7", err: ErrLT},
+		{give: "<a", exp: "xml: unexpected end of input at 1:1", err: ErrEOF},
+		{give: "< a/>", exp: "xml: expected a name at 1:2", err: ErrName},
+		{give: "<1/>", exp: "xml: expected a name at 1:2", err: ErrName},
+		{give: "<a/><b/>", exp: "xml: multiple root elements at 1:5", err: ErrRoot},
+		{give: "<a/>\n x", exp: "xml: content outside the root element at 2:2", err: ErrContent},
+		{give: "<![CDATA[x]]>", exp: "xml: content outside the root element at 1:1", err: ErrContent},
+		{give: "<a>]]></a>", exp: "xml: ']]>' in text at 1:4", err: ErrCDATAEnd},
+		{give: "<a><![CDATA[x</a>", exp: "xml: unexpected end of input at 1:4", err: ErrEOF},
+		{give: "<!-- a -- b -->", exp: "xml: '--' in comment at 1:8", err: ErrDashes},
+		{give: "<!-- a", exp: "xml: unexpected end of input at 1:1", err: ErrEOF},
+		{give: "<?pi x", exp: "xml: unexpected end of input at 1:1", err: ErrEOF},
+		{give: "\n<?xml version='1.0'?><a/>", exp: "xml: XML declaration not at the start at 2:1", err: ErrDecl},
+		{give: "<a/><!DOCTYPE a>", exp: "xml: misplaced DOCTYPE at 1:5", err: ErrDoctype},
+		{give: "<!DOCTYPE a [", exp: "xml: unexpected end of input at 1:1", err: ErrEOF},
+		{give: "", exp: "xml: missing root element at 1:1", err: ErrNoRoot},
+		{give: " \n ", exp: "xml: missing root element at 2:2", err: ErrNoRoot},
+		{give: "<?xml version='1.0'?>\n<!-- a -->\n", exp: "xml: missing root element at 3:1", err: ErrNoRoot},
+		{give: "<!DOCTYPE a>", exp: "xml: missing root element at 1:13", err: ErrNoRoot},
+	}
+	for _, tc := range tt {
+		_, err := tokens(NewTokenizer(tc.give))
+		if assertEqual(t, true, err != nil, tc.give) {
+			assertEqual(t, tc.exp, err.Error(), tc.give)
+			assertEqual(t, true, errors.Is(err, tc.err), tc.give)
+		}
+	}
+}
+
+func TestTokenizerNamespacesError(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+		err  error
+	}{
+		{give: "<m:a/>", exp: `xml: unbound namespace prefix "m" at 1:2`, err: ErrPrefix},
+		{give: "<a m:b='1'/>", exp: `xml: unbound namespace prefix "m" at 1:4`, err: ErrPrefix},
+		{give: "<a xmlns:m='u' xmlns:n='u' m:b='1' n:b='2'/>", exp: `xml: duplicate attribute "b" at 1:36`, err: ErrDuplicate},
+		{give: "<a xmlns:m='u'/><m:b/>", exp: "xml: multiple root elements at 1:17", err: ErrRoot},
+		{give: "<a><b xmlns:m='u'/><m:c/></a>", exp: `xml: unbound namespace prefix "m" at 1:21`, err: ErrPrefix},
+	}
+	for _, tc := range tt {
+		z := NewTokenizer(tc.give)
+		z.Namespaces = true
+		_, err := tokens(z)
+		if assertEqual(t, true, err != nil, tc.give) {
+			assertEqual(t, tc.exp, err.Error(), tc.give)
+			assertEqual(t, true, errors.Is(err, tc.err), tc.give)
+		}
+	}
+}
+
+func TestTokenizerErrorFinal(t *testing.T) {
+	z := NewTokenizer("<a></b><c/>")
+	z.Next()
+	_, err1 := z.Next()
+	_, err2 := z.Next()
+	assertEqual(t, true, errors.Is(err1, ErrMismatch))
+	assertEqual(t, err1, err2)
+
+	z = NewTokenizer("  <a/>  ")
+	z.Next()
+	z.Next()
+	_, err := z.Next()
+	assertEqual(t, io.EOF, err)
+}
+
+func TestTokenizerAllocs(t *testing.T) {
+	allocs := func(src string) float64 {
+		return testing.AllocsPerRun(10, func() {
+			z := NewTokenizer(src)
+			for {
+				if _, err := z.Next(); err != nil {
+					break
+				}
+			}
+		})
+	}
+	entry := `<entry id="1">text</entry>`
+	one := allocs("<feed>" + entry + "</feed>")
+	many := allocs("<feed>" + strings.Repeat(entry, 50) + "</feed>")
+	// Tokens don't allocate; only the tokenizer does.
+	assertEqual(t, one, many)
+}
+
+func BenchmarkTokenizer(b *testing.B) {
+	src := `<?xml version="1.0"?><feed xmlns="urn:atom">` +
+		strings.Repeat(`<entry id="1"><title>Go &amp; XML</title><link href="http://a.b/c"/><summary><![CDATA[<p>x</p>]]></summary></entry>`, 10) +
+		`</feed>`
+	b.SetBytes(int64(len(src)))
+	for i := 0; i < b.N; i++ {
+		z := NewTokenizer(src)
+		z.Namespaces = true
+		for {
+			if _, err := z.Next(); err != nil {
+				break
+			}
+		}
+	}
+}
+
+// #endregion Tokenizer
+
+// tokens prints the tokens of z.
+func tokens(z *Tokenizer) ([]string, error) {
+	var out []string
+	for {
+		tok, err := z.Next()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return out, err
+		}
+		s := tok.Kind.String()
+		switch tok.Kind {
+		case StartElement:
+			var attrs []string
+			for _, a := range tok.Attrs {
+				attrs = append(attrs, name(a.Name)+"="+a.Value)
+			}
+			s += " " + name(tok.Name) + " [" + strings.Join(attrs, " ") + "]"
+			if tok.SelfClosing {
+				s += " />"
+			}
+		case EndElement:
+			s += " " + name(tok.Name)
+		case ProcInst:
+			s += " " + name(tok.Name) + " " + fmt.Sprintf("%q", tok.Data)
+		default:
+			s += " " + fmt.Sprintf("%q", tok.Data)
+		}
+		out = append(out, s)
+	}
+}
+
+func name(n Name) string {
+	switch {
+	case n.Space == "":
+		return n.Local
+	case strings.Contains(n.Space, ":") && n.Space != "xmlns":
+		return "{" + n.Space + "}" + n.Local
+	}
+	return n.Space + ":" + n.Local
+}
+
+func assertEqual(t *testing.T, exp, got any, msgs ...any) bool {
+	t.Helper()
+	if !reflect.DeepEqual(exp, got) {
+		t.Errorf("\nExp:\n%v\nGot:\n%v\nMsg: %v", exp, got, fmt.Sprint(msgs...))
+		return false
+	}
+	return true
+}
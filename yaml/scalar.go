@@ -0,0 +1,296 @@
+package yaml
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// #region Scalar
+
+// scalar parses a plain or quoted scalar.
+func (p *parser) scalar(flow bool) (*Node, error) {
+	n := p.newNode(ScalarNode, p.s)
+	var err error
+	switch c := p.s.Curr(); c {
+	case '"':
+		n.Style = c
+		n.Value, err = p.double()
+	case '\'':
+		n.Style = c
+		n.Value, err = p.single()
+	default:
+		if n.Value = p.plain(flow); n.Value == "" {
+			return nil, p.errorf(p.s, ErrChar)
+		}
+	}
+	return n, err
+}
+
+// plain scans a plain scalar up to a ": ", a " #" or the
+// line end. In flow collections it also ends at ",[]{}".
+// The whitespaces at the end are skipped but not part of it.
+func (p *parser) plain(flow bool) string {
+	m, end := p.s, p.s
+	for p.s.More() {
+		c := p.s.Curr()
+		if c == '\n' || c == '\r' || flow && isFlow(c) ||
+			c == ':' && (p.indicator(1) || flow && len(p.s) > 1 && isFlow(p.s[1])) ||
+			c == '#' && len(m) > len(p.s) && isSpace(p.src[p.off(p.s)-1]) {
+			break
+		}
+		p.s.Next()
+		if !isSpace(c) {
+			end = p.s
+		}
+	}
+	return m[:len(m)-len(end)].String()
+}
+
+// more folds into v the next lines of a multi-line
+// plain scalar, which are indented more than parent.
+// A line break is a space and more are line feeds.
+func (p *parser) more(v string, parent int) string {
+	for {
+		s := p.s
+		s.MatchWhileByteBy(isSpace)
+		if !s.MatchByte('\n') && !s.Match("\r\n") {
+			return v
+		}
+		breaks, indent := 0, 0
+		for {
+			m := len(s)
+			s.MatchWhileByteBy(func(c byte) bool { return c == ' ' })
+			indent = m - len(s)
+			s.MatchWhileByteBy(isSpace)
+			if !s.MatchByte('\n') && !s.Match("\r\n") {
+				break
+			}
+			breaks++
+		}
+		if !s.More() || indent <= parent || s.EqualByte('#') ||
+			indent == 0 && (s.Equal("---") || s.Equal("...")) {
+			return v
+		}
+		save := p.s
+		p.s = s
+		text := p.plain(false)
+		if text == "" || p.s.EqualByte(':') {
+			// A key is not a continuation.
+			p.s = save
+			return v
+		}
+		if breaks == 0 {
+			v += " " + text
+		} else {
+			v += strings.Repeat("\n", breaks) + text
+		}
+	}
+}
+
+// double parses a double quoted scalar.
+func (p *parser) double() (string, error) {
+	ini := p.s
+	p.s.Next()
+	var b []byte
+	for {
+		c := p.s.Curr()
+		switch {
+		case !p.s.More():
+			return "", p.errorf(ini, ErrQuote)
+		case c == '"':
+			p.s.Next()
+			return string(b), nil
+		case c == '\\' && (p.s.Equal("\\\n") || p.s.Equal("\\\r\n")):
+			// An escaped line break joins the lines.
+			p.s.Next()
+			if !p.s.MatchByte('\n') {
+				p.s.Match("\r\n")
+			}
+			p.s.MatchWhileByteBy(isSpace)
+		case c == '\\':
+			var err error
+			if b, err = p.escape(b); err != nil {
+				return "", err
+			}
+		case c == '\n' || p.s.Equal("\r\n"):
+			b = p.fold(b)
+		default:
+			b = append(b, c)
+			p.s.Next()
+		}
+	}
+}
+
+// single parses a single quoted scalar.
+func (p *parser) single() (string, error) {
+	ini := p.s
+	p.s.Next()
+	var b []byte
+	for {
+		c := p.s.Curr()
+		switch {
+		case !p.s.More():
+			return "", p.errorf(ini, ErrQuote)
+		case p.s.Match("''"):
+			b = append(b, '\'')
+		case c == '\'':
+			p.s.Next()
+			return string(b), nil
+		case c == '\n' || p.s.Equal("\r\n"):
+			b = p.fold(b)
+		default:
+			b = append(b, c)
+			p.s.Next()
+		}
+	}
+}
+
+// fold folds the line breaks in a quoted scalar. One
+// is a space and more are one line feed less. The
+// whitespaces around them are trimmed.
+func (p *parser) fold(b []byte) []byte {
+	for len(b) > 0 && isSpace(b[len(b)-1]) {
+		b = b[:len(b)-1]
+	}
+	n := 0
+	for p.s.MatchByte('\n') || p.s.Match("\r\n") {
+		p.s.MatchWhileByteBy(isSpace)
+		n++
+	}
+	if n == 1 {
+		return append(b, ' ')
+	}
+	for ; n > 1; n-- {
+		b = append(b, '\n')
+	}
+	return b
+}
+
+// escapes are the single character escapes.
+var escapes = map[byte]string{
+	'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", '\t': "\t",
+	'n': "\n", 'v': "\v", 'f': "\f", 'r': "\r", 'e': "\x1b",
+	' ': " ", '"': "\"", '/': "/", '\\': "\\",
+	'N': "\u0085", '_': "\u00a0", 'L': "\u2028", 'P': "\u2029",
+}
+
+// escape decodes an escape sequence into b.
+func (p *parser) escape(b []byte) ([]byte, error) {
+	ini := p.s
+	p.s.Next()
+	c := p.s.Curr()
+	p.s.Next()
+	if v, ok := escapes[c]; ok {
+		return append(b, v...), nil
+	}
+	var n int
+	switch c {
+	case 'x':
+		n = 2
+	case 'u':
+		n = 4
+	case 'U':
+		n = 8
+	}
+	r, ok := p.s.UtilParseHexRune(n)
+	if !ok {
+		return nil, p.errorf(ini, ErrEscape)
+	}
+	return utf8.AppendRune(b, r), nil
+}
+
+// block parses a literal | or folded > block scalar. The
+// header can have a chomping indicator, - to strip the
+// final line breaks or + to keep them, and an indentation
+// indicator. Else the first line sets the indentation.
+func (p *parser) block(parent int) (*Node, error) {
+	n := p.newNode(ScalarNode, p.s)
+	n.Style = p.s.Curr()
+	p.s.Next()
+	var chomp byte
+	indent := -1
+	for i := 0; i < 2; i++ {
+		if c := p.s.Curr(); (c == '-' || c == '+') && chomp == 0 {
+			chomp = c
+		} else if c >= '1' && c <= '9' && indent < 0 {
+			indent = parent + int(c-'0')
+		} else {
+			break
+		}
+		p.s.Next()
+	}
+	p.ws()
+	if !p.atEOL() {
+		return nil, p.errorf(p.s, ErrTrailing)
+	}
+	if p.s.EqualByte('#') && !p.s.MatchUntilAnyByte('\r', '\n') {
+		p.s.Advance(len(p.s))
+	}
+	// Content lines, up to a less indented one.
+	var lines []string
+	s, end, last := p.s, p.s, 0
+	for s.MatchByte('\n') || s.Match("\r\n") {
+		m := s
+		s.MatchWhileByteBy(func(c byte) bool { return c == ' ' })
+		sp := len(m) - len(s)
+		blank := !s.More() || s.EqualByte('\n') || s.EqualByte('\r')
+		if !blank {
+			if indent < 0 {
+				if sp <= parent {
+					break
+				}
+				indent = sp
+			}
+			if sp < indent {
+				break
+			}
+		}
+		s = m
+		if indent >= 0 && sp > indent {
+			sp = indent
+		}
+		s.Advance(sp)
+		t := s
+		if !s.MatchUntilAnyByte('\r', '\n') {
+			s.Advance(len(s))
+		}
+		lines = append(lines, t[:len(t)-len(s)].String())
+		if !blank {
+			end, last = s, len(lines)
+		}
+	}
+	p.s = end
+	var b strings.Builder
+	breaks, prev := 0, ""
+	for _, l := range lines[:last] {
+		switch {
+		case l == "":
+			breaks++
+			continue
+		case prev == "":
+			b.WriteString(strings.Repeat("\n", breaks))
+		case n.Style == '|' || isSpace(l[0]) || isSpace(prev[0]):
+			b.WriteString(strings.Repeat("\n", breaks+1))
+		case breaks == 0:
+			b.WriteByte(' ')
+		default:
+			b.WriteString(strings.Repeat("\n", breaks))
+		}
+		b.WriteString(l)
+		prev, breaks = l, 0
+	}
+	trail := len(lines) - last
+	switch {
+	case chomp == '+':
+		b.WriteString(strings.Repeat("\n", breaks+trail))
+		if last > 0 {
+			b.WriteByte('\n')
+		}
+	case chomp == 0 && last > 0:
+		b.WriteByte('\n')
+	}
+	n.Value = b.String()
+	return n, p.next()
+}
+
+// #endregion Scalar
@@ -0,0 +1,109 @@
+package yaml
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// #region Scalar
+
+func TestDecodeScalar(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  any
+	}{
+		// Plain.
+		{give: "text", exp: "text"},
+		{give: "a b  c  # comment", exp: "a b  c"},
+		{give: "http://x.io:80/a#b", exp: "http://x.io:80/a#b"},
+		{give: "a\n  b\n\n  c\n  # end", exp: "a b\nc"},
+		{give: "-x", exp: "-x"},
+		// Core schema.
+		{give: "~", exp: nil},
+		{give: "null", exp: nil},
+		{give: "", exp: nil},
+		{give: "True", exp: true},
+		{give: "FALSE", exp: false},
+		{give: "yes", exp: "yes"},
+		{give: "-42", exp: int64(-42)},
+		{give: "+7", exp: int64(7)},
+		{give: "0o17", exp: int64(15)},
+		{give: "0xFF", exp: int64(255)},
+		{give: "0X1F", exp: int64(31)},
+		{give: "0O17", exp: int64(15)},
+		{give: "1.5e3", exp: 1500.0},
+		{give: ".5", exp: 0.5},
+		{give: "-.inf", exp: math.Inf(-1)},
+		{give: "1_000", exp: "1_000"},
+		{give: "1.2.3", exp: "1.2.3"},
+		{give: "99999999999999999999", exp: "99999999999999999999"},
+		// Quoted.
+		{give: `'it''s # not a comment'`, exp: "it's # not a comment"},
+		{give: `"1"`, exp: "1"},
+		{give: `"tab\tnl\nq\"\\ \x41é\U0001F600\/"`, exp: "tab\tnl\nq\"\\ Aé😀/"},
+		{give: "'a\n  b\n\n  c'", exp: "a b\nc"},
+		{give: "\"a  \n  b\\\n  c\"", exp: "a bc"},
+		// Block.
+		{give: "|\n  a\n   b\n\n  c\n", exp: "a\n b\n\nc\n"},
+		{give: "|-\n  a\n\n", exp: "a"},
+		{give: "|+\n  a\n", exp: "a\n\n"},
+		{give: "|2\n   a\n  b\n", exp: " a\nb\n"},
+		{give: "| # comment\n  # not a comment\n", exp: "# not a comment\n"},
+		{give: ">\n  a\n  b\n\n  c\n    d\n  e\n", exp: "a b\nc\n  d\ne\n"},
+		{give: ">-\n\n  a\n  b", exp: "\na b"},
+		{give: "|\n", exp: ""},
+	}
+	for _, tc := range tt {
+		got, err := Decode("k: " + tc.give + "\nz: 0")
+		assertEqual(t, nil, err, tc.give)
+		m, _ := got.(map[string]any)
+		assertEqual(t, tc.exp, m["k"], tc.give)
+		assertEqual(t, int64(0), m["z"], tc.give)
+	}
+	got, err := Decode(".nan")
+	assertEqual(t, nil, err)
+	assertEqual(t, true, math.IsNaN(got.(float64)))
+}
+
+func TestParseScalarStyle(t *testing.T) {
+	n, err := Parse("- a\n- 'b'\n- \"c\"\n- |\n  d\n- >\n  e")
+	assertEqual(t, nil, err)
+	var styles []byte
+	for _, c := range n.Content {
+		styles = append(styles, c.Style)
+	}
+	assertEqual(t, []byte{0, '\'', '"', '|', '>'}, styles)
+	assertEqual(t, [2]int{4, 3}, [2]int{n.Content[3].Line, n.Content[3].Col})
+}
+
+func TestParseScalarError(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+		err  error
+	}{
+		{give: `a: "open`, exp: "yaml: unclosed quoted scalar at 1:4", err: ErrQuote},
+		{give: "a: 'open\n\n", exp: "yaml: unclosed quoted scalar at 1:4", err: ErrQuote},
+		{give: `a: "\q"`, exp: "yaml: invalid escape at 1:5", err: ErrEscape},
+		{give: `a: "\uD800"`, exp: "yaml: invalid escape at 1:5", err: ErrEscape},
+		{give: `a: "x" y`, exp: "yaml: unexpected text after value at 1:8", err: ErrTrailing},
+		{give: "a: | x\n  b", exp: "yaml: unexpected text after value at 1:6", err: ErrTrailing},
+	}
+	for _, tc := range tt {
+		_, err := Parse(tc.give)
+		if assertEqual(t, true, err != nil, tc.give) {
+			assertEqual(t, tc.exp, err.Error(), tc.give)
+			assertEqual(t, true, errors.Is(err, tc.err), tc.give)
+		}
+	}
+}
+
+func BenchmarkParseScalar(b *testing.B) {
+	src := "a: plain text\nb: 'single'\nc: \"double\\n\"\nd: |\n  line\n  line\ne: >\n  folded\n  text\n"
+	for i := 0; i < b.N; i++ {
+		Parse(src)
+	}
+}
+
+// #endregion Scalar
@@ -0,0 +1,721 @@
+// Package yaml parses a subset of YAML 1.2 with the scanner:
+// block mappings and sequences, flow collections, plain,
+// quoted and block scalars, comments, anchors and aliases.
+// Tags, complex keys and multiple documents are not supported.
+package yaml
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ofabricio/scanner"
+)
+
+// #region Errors
+
+var (
+	ErrIndent    = errors.New("bad indentation")
+	ErrBlock     = errors.New("block collection not allowed here")
+	ErrKey       = errors.New("expected a mapping key")
+	ErrColon     = errors.New("expected ':' after mapping key")
+	ErrQuote     = errors.New("unclosed quoted scalar")
+	ErrEscape    = errors.New("invalid escape")
+	ErrFlow      = errors.New("expected ',' or end of flow collection")
+	ErrAnchor    = errors.New("unknown anchor")
+	ErrDuplicate = errors.New("duplicate key")
+	ErrChar      = errors.New("unexpected character")
+	ErrTag       = errors.New("tags are not supported")
+	ErrDocument  = errors.New("multiple documents are not supported")
+	ErrMerge     = errors.New("invalid merge")
+	ErrTrailing  = errors.New("unexpected text after value")
+)
+
+// ParseError is a positioned error.
+// Key is the key of ErrDuplicate or
+// the anchor of ErrAnchor errors.
+type ParseError = scanner.ParseError
+
+// #endregion Errors
+
+// #region Node
+
+// Kind is the kind of a Node.
+type Kind int
+
+const (
+	ScalarNode Kind = iota + 1
+	MappingNode
+	SequenceNode
+	AliasNode
+)
+
+func (k Kind) String() string {
+	switch k {
+	case ScalarNode:
+		return "Scalar"
+	case MappingNode:
+		return "Mapping"
+	case SequenceNode:
+		return "Sequence"
+	case AliasNode:
+		return "Alias"
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Node is a node of a document.
+type Node struct {
+	Kind Kind
+	// Value is the text of a scalar, with quotes
+	// and escapes decoded, or the name of an alias.
+	Value string
+	// Style is how a scalar was written: zero
+	// for plain, '\'', '"', '|' or '>'.
+	Style byte
+	// Flow tells if a collection was
+	// written like [a, b] or {a: b}.
+	Flow bool
+	// Anchor is the name of the node's &anchor.
+	Anchor string
+	// Alias is the node an alias refers to.
+	Alias *Node
+	// Content is the items of a sequence or the
+	// keys and values, in turn, of a mapping.
+	Content []*Node
+	Line    int // 1-based line of the node.
+	Col     int // 1-based column of the node.
+}
+
+// #endregion Node
+
+// #region Parse
+
+// Parse parses a document. It returns nil if there is
+// no node. A leading --- document marker is skipped.
+func Parse(src string) (*Node, error) {
+	s := scanner.Scanner(src)
+	p := parser{
+		src:      s,
+		s:        s,
+		lay:      scanner.Layout{Trivia: &scanner.TriviaShell},
+		anchors:  map[string]*Node{},
+		lastLine: 1,
+	}
+	if err := p.next(); err != nil || p.eof {
+		return nil, err
+	}
+	at := p.s
+	if p.marker("---") {
+		p.s.Advance(3)
+	}
+	n, err := p.value(-1, true, at)
+	if err != nil || p.eof {
+		return n, err
+	}
+	if p.marker("...") {
+		at := p.s
+		p.s.Advance(3)
+		if err := p.eol(); err != nil {
+			return nil, err
+		}
+		if p.eof {
+			return n, nil
+		}
+		return nil, p.errorf(at, ErrDocument)
+	}
+	if p.marker("---") {
+		return nil, p.errorf(p.s, ErrDocument)
+	}
+	return nil, p.errorf(p.s, ErrTrailing)
+}
+
+type parser struct {
+	src, s  scanner.Scanner
+	lay     scanner.Layout
+	indent  int  // Column of the current line.
+	eof     bool // No lines left.
+	anchors map[string]*Node
+	// Last position asked for.
+	lastOff, lastLine int
+}
+
+// value parses the node after a mapping ':', a sequence
+// '-' or a document start, at a block of column col. The
+// node is either on the same line or indented on the
+// next ones. Mapping values can also be sequences at col.
+func (p *parser) value(col int, item bool, at scanner.Scanner) (*Node, error) {
+	p.ws()
+	anchor, err := p.anchor()
+	if err != nil {
+		return nil, err
+	}
+	var n *Node
+	if p.atEOL() {
+		if err := p.eol(); err != nil {
+			return nil, err
+		}
+		if !p.eof && (p.indent > col || !item && p.indent == col && p.dash()) {
+			n, err = p.node(col, true)
+		} else {
+			n = p.newNode(ScalarNode, at)
+		}
+	} else {
+		n, err = p.node(col, item)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if anchor != "" {
+		n.Anchor = anchor
+		p.anchors[anchor] = n
+	}
+	return n, nil
+}
+
+// anchor parses an optional &anchor.
+func (p *parser) anchor() (string, error) {
+	if p.s.EqualByte('!') {
+		return "", p.errorf(p.s, ErrTag)
+	}
+	if !p.s.MatchByte('&') {
+		return "", nil
+	}
+	name := p.name()
+	if name == "" {
+		return "", p.errorf(p.s, ErrChar)
+	}
+	p.ws()
+	return name, nil
+}
+
+// name scans an anchor or alias name.
+func (p *parser) name() string {
+	return p.s.TokenByteBy(func(c byte) bool {
+		return !isSpace(c) && c != '\n' && c != '\r' && !isFlow(c)
+	})
+}
+
+// node parses a node at the current position. Parent is
+// the column of the enclosing block, which block scalars
+// and scalar lines must be indented more than. Block tells
+// if block collections can start here. The parser ends
+// at the next line.
+func (p *parser) node(parent int, block bool) (*Node, error) {
+	at := p.s
+	col := p.col(at)
+	switch c := p.s.Curr(); {
+	case c == '*':
+		p.s.Next()
+		name := p.name()
+		target, ok := p.anchors[name]
+		if !ok {
+			line, col := p.pos(at)
+			return nil, &ParseError{Line: line, Col: col, Err: ErrAnchor, Pkg: "yaml", Key: name}
+		}
+		n := p.newNode(AliasNode, at)
+		n.Value, n.Alias = name, target
+		return n, p.eol()
+	case c == '!':
+		return nil, p.errorf(at, ErrTag)
+	case p.dash():
+		if !block {
+			return nil, p.errorf(at, ErrBlock)
+		}
+		return p.sequence(col)
+	case c == '[' || c == '{':
+		n, err := p.flow()
+		if err != nil {
+			return nil, err
+		}
+		return n, p.eol()
+	case c == '|' || c == '>':
+		return p.block(parent)
+	case strings.IndexByte(",]}#%@`&", c) >= 0 || (c == '?' || c == ':') && p.indicator(1):
+		return nil, p.errorf(at, ErrChar)
+	}
+	n, err := p.scalar(false)
+	if err != nil {
+		return nil, err
+	}
+	p.ws()
+	if p.s.EqualByte(':') && p.indicator(1) {
+		if !block {
+			return nil, p.errorf(at, ErrBlock)
+		}
+		return p.mapping(col, n)
+	}
+	if n.Style == 0 {
+		n.Value = p.more(n.Value, parent)
+	}
+	return n, p.eol()
+}
+
+// mapping parses a block mapping at column col,
+// from the ':' after its first key.
+func (p *parser) mapping(col int, key *Node) (*Node, error) {
+	p.lay.Push(col)
+	m := &Node{Kind: MappingNode, Line: key.Line, Col: key.Col}
+	for {
+		if err := p.add(m, key); err != nil {
+			return nil, err
+		}
+		at := p.s
+		p.s.Next()
+		v, err := p.value(col, false, at)
+		if err != nil {
+			return nil, err
+		}
+		m.Content = append(m.Content, key, v)
+		if p.end(col) {
+			return m, nil
+		}
+		if p.indent > col {
+			return nil, p.errorf(p.s, ErrIndent)
+		}
+		if c := p.s.Curr(); strings.IndexByte("[{*&!|>,]}#%@`", c) >= 0 || p.dash() || (c == '?' || c == ':') && p.indicator(1) {
+			return nil, p.errorf(p.s, ErrKey)
+		}
+		if key, err = p.scalar(false); err != nil {
+			return nil, err
+		}
+		p.ws()
+		if !p.s.EqualByte(':') || !p.indicator(1) {
+			return nil, p.errorf(p.s, ErrColon)
+		}
+	}
+}
+
+// add checks that key is not in mapping m yet.
+func (p *parser) add(m *Node, key *Node) error {
+	for i := 0; i < len(m.Content); i += 2 {
+		if k := m.Content[i]; k.Kind == ScalarNode && k.Value == key.Value {
+			return &ParseError{Line: key.Line, Col: key.Col, Err: ErrDuplicate, Pkg: "yaml", Key: key.Value}
+		}
+	}
+	return nil
+}
+
+// sequence parses a block sequence at column col.
+func (p *parser) sequence(col int) (*Node, error) {
+	p.lay.Push(col)
+	n := p.newNode(SequenceNode, p.s)
+	for {
+		at := p.s
+		p.s.Next()
+		v, err := p.value(col, true, at)
+		if err != nil {
+			return nil, err
+		}
+		n.Content = append(n.Content, v)
+		if p.end(col) {
+			return n, nil
+		}
+		if p.indent > col {
+			return nil, p.errorf(p.s, ErrIndent)
+		}
+		if !p.dash() {
+			// A sequence that is a mapping value
+			// at the column of the mapping keys.
+			return n, nil
+		}
+	}
+}
+
+// flow parses a flow collection, which can span lines.
+func (p *parser) flow() (*Node, error) {
+	at := p.s
+	kind, end := SequenceNode, byte(']')
+	if p.s.Curr() == '{' {
+		kind, end = MappingNode, '}'
+	}
+	n := p.newNode(kind, at)
+	n.Flow = true
+	p.s.Next()
+	for {
+		p.fws()
+		if p.s.MatchByte(end) {
+			return n, nil
+		}
+		if !p.s.More() {
+			return nil, p.errorf(p.s, ErrFlow)
+		}
+		key, err := p.flowNode()
+		if err != nil {
+			return nil, err
+		}
+		p.fws()
+		var val *Node
+		if p.s.MatchByte(':') {
+			p.fws()
+			if p.s.EqualByte(',') || p.s.EqualByte(end) {
+				val = p.newNode(ScalarNode, p.s)
+			} else if val, err = p.flowNode(); err != nil {
+				return nil, err
+			}
+		} else if kind == MappingNode {
+			val = p.newNode(ScalarNode, p.s)
+		}
+		switch {
+		case kind == MappingNode:
+			if err := p.add(n, key); err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, key, val)
+		case val != nil:
+			// A single pair mapping, like [a: b].
+			pair := &Node{Kind: MappingNode, Flow: true, Content: []*Node{key, val}, Line: key.Line, Col: key.Col}
+			n.Content = append(n.Content, pair)
+		default:
+			n.Content = append(n.Content, key)
+		}
+		p.fws()
+		if !p.s.MatchByte(',') && !p.s.EqualByte(end) {
+			return nil, p.errorf(p.s, ErrFlow)
+		}
+	}
+}
+
+// flowNode parses a node in a flow collection.
+func (p *parser) flowNode() (*Node, error) {
+	at := p.s
+	anchor, err := p.anchor()
+	if err != nil {
+		return nil, err
+	}
+	var n *Node
+	switch c := p.s.Curr(); {
+	case c == '*':
+		p.s.Next()
+		name := p.name()
+		target, ok := p.anchors[name]
+		if !ok {
+			line, col := p.pos(at)
+			return nil, &ParseError{Line: line, Col: col, Err: ErrAnchor, Pkg: "yaml", Key: name}
+		}
+		n = p.newNode(AliasNode, at)
+		n.Value, n.Alias = name, target
+	case c == '[' || c == '{':
+		n, err = p.flow()
+	case strings.IndexByte(",]}#%@`|>!&", c) >= 0 || c == '-' && p.indicator(1):
+		return nil, p.errorf(p.s, ErrChar)
+	default:
+		n, err = p.scalar(true)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if anchor != "" {
+		n.Anchor = anchor
+		p.anchors[anchor] = n
+	}
+	return n, nil
+}
+
+// next moves to the content of the next
+// non-blank line, or sets eof.
+func (p *parser) next() error {
+	for {
+		k, err := p.lay.Next(&p.s)
+		if err != nil {
+			var e *scanner.LayoutError
+			errors.As(err, &e)
+			return p.errorf(p.src[e.Pos:], e.Err)
+		}
+		switch k {
+		case scanner.LayoutNone:
+			p.indent = p.col(p.s)
+			return nil
+		case scanner.LayoutEOF:
+			p.eof = true
+			return nil
+		}
+	}
+}
+
+// eol skips the rest of a line, which can only
+// be a comment, and moves to the next line.
+func (p *parser) eol() error {
+	p.ws()
+	if !p.atEOL() {
+		return p.errorf(p.s, ErrTrailing)
+	}
+	if p.s.EqualByte('#') {
+		p.s.MatchUntilAnyByte('\r', '\n')
+		if p.s.EqualByte('#') {
+			p.s.Advance(len(p.s))
+		}
+	}
+	return p.next()
+}
+
+// atEOL tells if the line ends or a comment starts.
+// Comments must be after a whitespace.
+func (p *parser) atEOL() bool {
+	if p.s.EqualByte('#') {
+		off := p.off(p.s)
+		return off == 0 || isSpace(p.src[off-1]) || p.src[off-1] == '\n'
+	}
+	return !p.s.More() || p.s.EqualByte('\n') || p.s.Equal("\r\n")
+}
+
+// fws skips whitespaces, line breaks and comments in flow collections.
+func (p *parser) fws() {
+	for {
+		switch {
+		case p.ws(), p.s.MatchByte('\n'), p.s.Match("\r\n"):
+		case p.s.EqualByte('#') && p.atEOL():
+			if !p.s.MatchUntilByte('\n') {
+				p.s.Advance(len(p.s))
+			}
+		default:
+			return
+		}
+	}
+}
+
+// ws skips spaces and tabs.
+func (p *parser) ws() bool {
+	return p.s.MatchWhileByteBy(isSpace)
+}
+
+// end tells if a block at column col ends.
+func (p *parser) end(col int) bool {
+	return p.eof || p.indent < col || p.marker("---") || p.marker("...")
+}
+
+// dash tells if a block sequence item starts.
+func (p *parser) dash() bool {
+	return p.s.EqualByte('-') && p.indicator(1)
+}
+
+// marker tells if a document marker, like ---, starts at column 0.
+func (p *parser) marker(v string) bool {
+	return p.col(p.s) == 0 && p.s.Equal(v) && p.indicator(len(v))
+}
+
+// indicator tells if the byte at i is a separator.
+func (p *parser) indicator(i int) bool {
+	return i >= len(p.s) || isSpace(p.s[i]) || p.s[i] == '\n' || p.s[i] == '\r'
+}
+
+// col returns the 0-based column of a mark.
+func (p *parser) col(at scanner.Scanner) int {
+	off := p.off(at)
+	return off - strings.LastIndexByte(string(p.src[:off]), '\n') - 1
+}
+
+// pos returns the 1-based line and column of a mark. It
+// counts lines from the last position asked for, which
+// is usually before, instead of from the start.
+func (p *parser) pos(at scanner.Scanner) (line, col int) {
+	off := p.off(at)
+	if off < p.lastOff {
+		p.lastOff, p.lastLine = 0, 1
+	}
+	p.lastLine += strings.Count(string(p.src[p.lastOff:off]), "\n")
+	p.lastOff = off
+	return p.lastLine, p.col(at) + 1
+}
+
+func (p *parser) newNode(k Kind, at scanner.Scanner) *Node {
+	n := &Node{Kind: k}
+	n.Line, n.Col = p.pos(at)
+	return n
+}
+
+func (p *parser) off(at scanner.Scanner) int {
+	return len(p.src) - len(at)
+}
+
+func (p *parser) errorf(at scanner.Scanner, err error) error {
+	line, col := p.pos(at)
+	return &ParseError{Line: line, Col: col, Err: err, Pkg: "yaml"}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+func isFlow(c byte) bool {
+	return c == ',' || c == '[' || c == ']' || c == '{' || c == '}'
+}
+
+// #endregion Parse
+
+// #region Decode
+
+// Decode parses a document into Go values. Mappings are
+// map[string]any and sequences []any. Plain scalars are
+// nil, bool, int64, float64 or string, as in the YAML 1.2
+// core schema, and other scalars are string. Merge keys,
+// like <<: *base, add the keys of mappings that are missing.
+func Decode(src string) (any, error) {
+	n, err := Parse(src)
+	if err != nil || n == nil {
+		return nil, err
+	}
+	return n.Decode()
+}
+
+// Decode returns the Go value of a node, like the Decode function.
+// A node with an anchor is decoded once, and its aliases share
+// the value, so nested aliases don't expand exponentially.
+func (n *Node) Decode() (any, error) {
+	d := decoder{anchors: map[*Node]any{}}
+	return d.decode(n)
+}
+
+// decoder decodes nodes, keeping the values of anchored nodes.
+type decoder struct {
+	anchors map[*Node]any
+}
+
+func (d *decoder) decode(n *Node) (any, error) {
+	if n.Kind == AliasNode {
+		n = n.Alias
+	}
+	if n.Anchor == "" {
+		return d.value(n)
+	}
+	if v, ok := d.anchors[n]; ok {
+		return v, nil
+	}
+	v, err := d.value(n)
+	if err != nil {
+		return nil, err
+	}
+	d.anchors[n] = v
+	return v, nil
+}
+
+// value returns the Go value of a node that isn't an alias.
+func (d *decoder) value(n *Node) (any, error) {
+	switch n.Kind {
+	case SequenceNode:
+		list := make([]any, len(n.Content))
+		for i, c := range n.Content {
+			v, err := d.decode(c)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = v
+		}
+		return list, nil
+	case MappingNode:
+		m := make(map[string]any, len(n.Content)/2)
+		if err := d.merge(n, m); err != nil {
+			return nil, err
+		}
+		for i := 0; i < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			if k.isMerge() {
+				continue
+			}
+			val, err := d.decode(v)
+			if err != nil {
+				return nil, err
+			}
+			m[k.Value] = val
+		}
+		return m, nil
+	}
+	if n.Style != 0 {
+		return n.Value, nil
+	}
+	return resolve(n.Value), nil
+}
+
+// merge adds to m the keys of the mappings of the merge keys of n.
+// The first mappings take precedence.
+func (d *decoder) merge(n *Node, m map[string]any) error {
+	for i := 0; i < len(n.Content); i += 2 {
+		if !n.Content[i].isMerge() {
+			continue
+		}
+		v := n.Content[i+1].resolve()
+		list := []*Node{v}
+		if v.Kind == SequenceNode {
+			list = v.Content
+		}
+		for _, c := range list {
+			c = c.resolve()
+			if c.Kind != MappingNode {
+				return &ParseError{Line: c.Line, Col: c.Col, Err: ErrMerge, Pkg: "yaml"}
+			}
+			src, err := d.decode(c)
+			if err != nil {
+				return err
+			}
+			for k, v := range src.(map[string]any) {
+				if _, ok := m[k]; !ok {
+					m[k] = v
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isMerge tells if n is the merge key.
+func (n *Node) isMerge() bool {
+	return n.Kind == ScalarNode && n.Style == 0 && n.Value == "<<"
+}
+
+// resolve returns the node an alias refers to.
+func (n *Node) resolve() *Node {
+	if n.Kind == AliasNode {
+		return n.Alias
+	}
+	return n
+}
+
+// number is the YAML 1.2 core schema number format.
+var number = scanner.NumberFormat{
+	Sign: true, Hex: true, Octal: true, LeadingZeros: true,
+	Float: true, BareDot: true,
+}
+
+// resolve returns the value of a plain scalar.
+func resolve(v string) any {
+	switch v {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		return math.Inf(1)
+	case "-.inf", "-.Inf", "-.INF":
+		return math.Inf(-1)
+	case ".nan", ".NaN", ".NAN":
+		return math.NaN()
+	}
+	s := scanner.Scanner(v)
+	if !s.UtilMatchNumberFormat(number) || s.More() {
+		return v
+	}
+	switch {
+	case strings.HasPrefix(v, "0x") || strings.HasPrefix(v, "0X"):
+		if n, err := strconv.ParseInt(v[2:], 16, 64); err == nil {
+			return n
+		}
+	case strings.HasPrefix(v, "0o") || strings.HasPrefix(v, "0O"):
+		if n, err := strconv.ParseInt(v[2:], 8, 64); err == nil {
+			return n
+		}
+	case strings.ContainsAny(v, ".eE"):
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	default:
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	}
+	return v
+}
+
+// #endregion Decode
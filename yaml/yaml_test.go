@@ -0,0 +1,187 @@
+package yaml
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ofabricio/scanner"
+)
+
+// #region Parse
+
+func TestParse(t *testing.T) {
+	src := "# ci\nname: build\nsteps:\n  - run: make\n    env: {A: 1}\n  - &t test\n  - *t\n"
+	n, err := Parse(src)
+	assertEqual(t, nil, err)
+	assertEqual(t, MappingNode, n.Kind)
+	assertEqual(t, [2]int{2, 1}, [2]int{n.Line, n.Col})
+	assertEqual(t, 4, len(n.Content))
+	steps := n.Content[3]
+	assertEqual(t, SequenceNode, steps.Kind)
+	assertEqual(t, [2]int{4, 3}, [2]int{steps.Line, steps.Col})
+	run := steps.Content[0]
+	assertEqual(t, MappingNode, run.Kind)
+	assertEqual(t, [2]int{4, 5}, [2]int{run.Line, run.Col})
+	env := run.Content[3]
+	assertEqual(t, true, env.Flow)
+	assertEqual(t, [2]int{5, 10}, [2]int{env.Line, env.Col})
+	test := steps.Content[1]
+	assertEqual(t, "t", test.Anchor)
+	alias := steps.Content[2]
+	assertEqual(t, AliasNode, alias.Kind)
+	assertEqual(t, "t", alias.Value)
+	assertEqual(t, test, alias.Alias)
+	assertEqual(t, [2]int{7, 5}, [2]int{alias.Line, alias.Col})
+}
+
+func TestParseEmpty(t *testing.T) {
+	for _, give := range []string{"", "\n", "# only\n", "---\n"} {
+		n, err := Parse(give)
+		assertEqual(t, nil, err, give)
+		if give == "---\n" {
+			assertEqual(t, ScalarNode, n.Kind, give)
+			continue
+		}
+		assertEqual(t, (*Node)(nil), n, give)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  string
+		err  error
+	}{
+		{give: "a: 1\na: 2", exp: `yaml: duplicate key "a" at 2:1`, err: ErrDuplicate},
+		{give: "x: {a: 1, a: 2}", exp: `yaml: duplicate key "a" at 1:11`, err: ErrDuplicate},
+		{give: "a: 1\n  b: 2", exp: "yaml: bad indentation at 2:3", err: ErrIndent},
+		{give: "a:\n    b: 1\n  c: 2", exp: "yaml: dedent does not match any outer indentation level at 3:3", err: scanner.ErrDedent},
+		{give: "a:\n\tb: 1", exp: "yaml: tab in indentation at 2:1", err: scanner.ErrTab},
+		{give: "a: b: c", exp: "yaml: block collection not allowed here at 1:4", err: ErrBlock},
+		{give: "a: - b", exp: "yaml: block collection not allowed here at 1:4", err: ErrBlock},
+		{give: "a: 1\n- b", exp: "yaml: expected a mapping key at 2:1", err: ErrKey},
+		{give: "a: 1\nb", exp: "yaml: expected ':' after mapping key at 2:2", err: ErrColon},
+		{give: "- a\nb: 1", exp: "yaml: unexpected text after value at 2:1", err: ErrTrailing},
+		{give: "a: [1, 2", exp: "yaml: expected ',' or end of flow collection at 1:9", err: ErrFlow},
+		{give: "a: ['x' 'y']", exp: "yaml: expected ',' or end of flow collection at 1:9", err: ErrFlow},
+		{give: "a: {b: 1} c", exp: "yaml: unexpected text after value at 1:11", err: ErrTrailing},
+		{give: "a: *nope", exp: `yaml: unknown anchor "nope" at 1:4`, err: ErrAnchor},
+		{give: "a: !!str 1", exp: "yaml: tags are not supported at 1:4", err: ErrTag},
+		{give: "a: @x", exp: "yaml: unexpected character at 1:4", err: ErrChar},
+		{give: "a: 1\n---\nb: 2", exp: "yaml: multiple documents are not supported at 2:1", err: ErrDocument},
+		{give: "a: 1\n...\nb: 2", exp: "yaml: multiple documents are not supported at 2:1", err: ErrDocument},
+	}
+	for _, tc := range tt {
+		_, err := Parse(tc.give)
+		if assertEqual(t, true, err != nil, tc.give) {
+			assertEqual(t, tc.exp, err.Error(), tc.give)
+			assertEqual(t, true, errors.Is(err, tc.err), tc.give)
+		}
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	src := "name: build\non: [push]\njobs:\n  test:\n    runs-on: ubuntu\n    steps:\n      - uses: checkout\n      - run: |\n          go test ./...\n"
+	for i := 0; i < b.N; i++ {
+		Parse(src)
+	}
+}
+
+// #endregion Parse
+
+// #region Decode
+
+func TestDecode(t *testing.T) {
+	tt := []struct {
+		give string
+		exp  any
+	}{
+		{give: "a: 1\nb:\n  c: x\n  d: [1, two]", exp: map[string]any{"a": int64(1), "b": map[string]any{"c": "x", "d": []any{int64(1), "two"}}}},
+		{give: "- a\n- - b\n  - c\n- d: 1\n  e: 2", exp: []any{"a", []any{"b", "c"}, map[string]any{"d": int64(1), "e": int64(2)}}},
+		{give: "- a:\n    x: 1\n  b: 2", exp: []any{map[string]any{"a": map[string]any{"x": int64(1)}, "b": int64(2)}}},
+		{give: "k:\n- a\n- b\nn: 1", exp: map[string]any{"k": []any{"a", "b"}, "n": int64(1)}},
+		{give: "a:\nb: # c\n  # d\nc: ~", exp: map[string]any{"a": nil, "b": nil, "c": nil}},
+		{give: "-\n  a: 1\n-\n- x", exp: []any{map[string]any{"a": int64(1)}, nil, "x"}},
+		{give: "{a: [1, {b: c}], 'd': \"e\", f}", exp: map[string]any{"a": []any{int64(1), map[string]any{"b": "c"}}, "d": "e", "f": nil}},
+		{give: "[a: 1, b]", exp: []any{map[string]any{"a": int64(1)}, "b"}},
+		{give: "a: [\n  1, # one\n  2,\n]\nb: 1", exp: map[string]any{"a": []any{int64(1), int64(2)}, "b": int64(1)}},
+		{give: "\"a b\": 1\n'c': 2\nd e: 3", exp: map[string]any{"a b": int64(1), "c": int64(2), "d e": int64(3)}},
+		{give: "---\na: 1\n...\n", exp: map[string]any{"a": int64(1)}},
+		{give: "--- text", exp: "text"},
+		{give: "a: 1\r\nb:\r\n  - 2\r\n", exp: map[string]any{"a": int64(1), "b": []any{int64(2)}}},
+	}
+	for _, tc := range tt {
+		got, err := Decode(tc.give)
+		assertEqual(t, nil, err, tc.give)
+		assertEqual(t, tc.exp, got, tc.give)
+	}
+}
+
+func TestDecodeAlias(t *testing.T) {
+	src := `base: &base
+  image: go
+  env: dev
+more: &more {cache: true}
+test:
+  <<: *base
+  env: ci
+both:
+  <<: [*more, *base]
+  image: alpine
+list: &l [1, 2]
+copy: *l
+`
+	got, err := Decode(src)
+	assertEqual(t, nil, err)
+	assertEqual(t, map[string]any{
+		"base": map[string]any{"image": "go", "env": "dev"},
+		"more": map[string]any{"cache": true},
+		"test": map[string]any{"image": "go", "env": "ci"},
+		"both": map[string]any{"image": "alpine", "env": "dev", "cache": true},
+		"list": []any{int64(1), int64(2)},
+		"copy": []any{int64(1), int64(2)},
+	}, got)
+
+	_, err = Decode("a: &a 1\nb:\n  <<: *a")
+	assertEqual(t, "yaml: invalid merge at 1:7", fmt.Sprint(err))
+	assertEqual(t, true, errors.Is(err, ErrMerge))
+}
+
+func TestDecodeAliasLaughs(t *testing.T) {
+	// Each level has 10 aliases of the one before, so
+	// expanding every alias would make 10^9 strings.
+	src := "a: &a [lol, lol, lol, lol, lol, lol, lol, lol, lol, lol]\n"
+	for c := 'b'; c <= 'j'; c++ {
+		p := "*" + string(c-1)
+		src += string(c) + ": &" + string(c) + " [" + strings.Repeat(p+", ", 9) + p + "]\n"
+	}
+	got, err := Decode(src)
+	assertEqual(t, nil, err)
+	v := got.(map[string]any)["j"]
+	for c := 'j'; c > 'a'; c-- {
+		assertEqual(t, 10, len(v.([]any)))
+		v = v.([]any)[9]
+	}
+	assertEqual(t, "lol", v.([]any)[9])
+}
+
+func BenchmarkDecode(b *testing.B) {
+	src := "defaults: &d\n  image: go\njobs:\n  - <<: *d\n    run: [make, test]\n  - <<: *d\n    run: lint\n"
+	for i := 0; i < b.N; i++ {
+		Decode(src)
+	}
+}
+
+// #endregion Decode
+
+func assertEqual(t *testing.T, exp, got any, msgs ...any) bool {
+	t.Helper()
+	if !reflect.DeepEqual(exp, got) {
+		t.Errorf("\nExp:\n%v\nGot:\n%v\nMsg: %v", exp, got, fmt.Sprint(msgs...))
+		return false
+	}
+	return true
+}